@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigIntPtrType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatPtrType = reflect.TypeOf((*big.Float)(nil))
+)
+
+// parseBigInts parses the decimal bounds, reporting false if any of them is malformed.
+func parseBigInts(bounds ...string) ([]*big.Int, bool) {
+	res := make([]*big.Int, 0, len(bounds))
+	for _, bound := range bounds {
+		n, ok := new(big.Int).SetString(bound, 10)
+		if !ok {
+			return nil, false
+		}
+		res = append(res, n)
+	}
+	return res, true
+}
+
+// validateBigInt checks a *big.Int value against the optional min and max bounds; nil values pass.
+func validateBigInt(v reflect.Value, min, max *big.Int) (bool, error) {
+	if v.IsNil() {
+		return true, nil
+	}
+	if !v.CanInterface() {
+		return false, ValidationError{Err: ErrValidateForUnexportedFields}
+	}
+	n := v.Interface().(*big.Int)
+	if min != nil && n.Cmp(min) < 0 {
+		return false, ValidationError{Err: errors.New("Integer is less than allowed")}
+	}
+	if max != nil && n.Cmp(max) > 0 {
+		return false, ValidationError{Err: errors.New("Integer is more than allowed")}
+	}
+	return true, nil
+}
+
+// bigFloatPrec returns the precision of a *big.Float value to parse its bounds with, so that a bound such as
+// "0.1" rounds the way the value did, or 64 for a nil or zero-precision value.
+func bigFloatPrec(v reflect.Value) uint {
+	if v.IsNil() || !v.CanInterface() || v.Interface().(*big.Float).Prec() == 0 {
+		return 64
+	}
+	return v.Interface().(*big.Float).Prec()
+}
+
+// parseBigFloats parses the bounds with big.ParseFloat at the precision prec, reporting false if any of them is malformed.
+func parseBigFloats(prec uint, bounds ...string) ([]*big.Float, bool) {
+	res := make([]*big.Float, 0, len(bounds))
+	for _, bound := range bounds {
+		f, _, err := big.ParseFloat(bound, 10, prec, big.ToNearestEven)
+		if err != nil {
+			return nil, false
+		}
+		res = append(res, f)
+	}
+	return res, true
+}
+
+// validateBigFloat checks a *big.Float value against the optional min and max bounds; nil values pass.
+func validateBigFloat(v reflect.Value, min, max *big.Float) (bool, error) {
+	if v.IsNil() {
+		return true, nil
+	}
+	if !v.CanInterface() {
+		return false, ValidationError{Err: ErrValidateForUnexportedFields}
+	}
+	f := v.Interface().(*big.Float)
+	if min != nil && f.Cmp(min) < 0 {
+		return false, ValidationError{Err: errors.New("Number is less than allowed")}
+	}
+	if max != nil && f.Cmp(max) > 0 {
+		return false, ValidationError{Err: errors.New("Number is more than allowed")}
+	}
+	return true, nil
+}
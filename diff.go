@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidateDiff validates two values of the same struct type, e.g. a config before and after an edit,
+// and returns the fields that were invalid in old but are valid in new (fixed) and the other way round
+// (broken), in field declaration order. The fields of nested structs are named by their path, e.g.
+// "Address.City".
+func ValidateDiff(old, new any) (fixed []string, broken []string, err error) {
+	oldValue, err := structValue(old)
+	if err != nil {
+		return nil, nil, err
+	}
+	newValue, err := structValue(new)
+	if err != nil {
+		return nil, nil, err
+	}
+	if oldValue.Type() != newValue.Type() {
+		return nil, nil, errors.Errorf("Can't compare values of different types %s and %s", oldValue.Type(), newValue.Type())
+	}
+	oldRes, err := check(old, Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+	newRes, err := check(new, Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+	oldInvalid, newInvalid := invalidFields(oldRes.errors), invalidFields(newRes.errors)
+	for _, name := range fieldPaths(oldValue.Type(), oldRes.errors, newRes.errors) {
+		_, wasInvalid := oldInvalid[name]
+		_, isInvalid := newInvalid[name]
+		switch {
+		case wasInvalid && !isInvalid:
+			fixed = append(fixed, name)
+		case !wasInvalid && isInvalid:
+			broken = append(broken, name)
+		}
+	}
+	return fixed, broken, nil
+}
+
+// invalidFields returns the set of fields having a violation in vs.
+func invalidFields(vs ValidationErrors) map[string]struct{} {
+	res := make(map[string]struct{}, len(vs))
+	for _, ve := range vs {
+		res[ve.Field] = struct{}{}
+	}
+	return res
+}
+
+// fieldPaths returns the fields having a violation in any of vss, sorted in the declaration order of t and
+// of its nested structs.
+func fieldPaths(t reflect.Type, vss ...ValidationErrors) []string {
+	var res []string
+	indexes := make(map[string][]int)
+	for _, vs := range vss {
+		for _, ve := range vs {
+			if _, ok := indexes[ve.Field]; ok {
+				continue
+			}
+			indexes[ve.Field] = fieldIndex(t, ve.Field)
+			res = append(res, ve.Field)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := indexes[res[i]], indexes[res[j]]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return res
+}
+
+// fieldIndex returns the index sequence of the field of t at path, e.g. "Address.City", through the
+// pointers to nested structs.
+func fieldIndex(t reflect.Type, path string) []int {
+	var res []int
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			break
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			break
+		}
+		res = append(res, f.Index...)
+		t = f.Type
+	}
+	return res
+}
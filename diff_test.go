@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDiff(t *testing.T) {
+	type config struct {
+		Name    string `validate:"min:3"`
+		Port    int    `validate:"between:1,65535"`
+		Mode    string `validate:"in:dev,prod"`
+		Comment string
+	}
+	old := config{Name: "ab", Port: 8080, Mode: "test"}
+	edited := config{Name: "abc", Port: 0, Mode: "test", Comment: "edited"}
+
+	fixed, broken, err := ValidateDiff(old, edited)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name"}, fixed)
+	assert.Equal(t, []string{"Port"}, broken)
+
+	fixed, broken, err = ValidateDiff(old, old)
+	assert.NoError(t, err)
+	assert.Empty(t, fixed)
+	assert.Empty(t, broken)
+
+	type pAddr struct {
+		City string `validate:"min:3"`
+		Zip  string `validate:"len:5"`
+	}
+	type profile struct {
+		Name string `validate:"min:1"`
+		Addr pAddr
+		Home *pAddr
+		Tag  string `validate:"max:3"`
+	}
+	before := profile{Name: "bob", Addr: pAddr{"ab", "12345"}, Home: &pAddr{"Rome", "1"}, Tag: "abcd"}
+	after := profile{Addr: pAddr{"abcd", "1"}, Home: &pAddr{"Rome", "12345"}, Tag: "abcd"}
+	fixed, broken, err = ValidateDiff(before, after)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Addr.City", "Home.Zip"}, fixed)
+	assert.Equal(t, []string{"Name", "Addr.Zip"}, broken)
+
+	fixed, broken, err = ValidateDiff(struct{ Addr pAddr }{pAddr{"ab", "12345"}}, struct{ Addr pAddr }{pAddr{"abcd", "12345"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Addr.City"}, fixed)
+	assert.Empty(t, broken)
+
+	_, _, err = ValidateDiff(old, struct{ Name string }{})
+	assert.EqualError(t, err, "Can't compare values of different types validation.config and struct { Name string }")
+	_, _, err = ValidateDiff(old, &edited)
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	enumsMu sync.RWMutex
+	enums   = make(map[reflect.Type]map[any]struct{})
+)
+
+// RegisterEnum stores the valid values of the type T, so that the fields of that type, or slices of it,
+// can be checked with "enum:" instead of listing the values in an "in" rule, e.g.
+// RegisterEnum([]Status{Active, Closed}). It replaces the values previously registered for T.
+func RegisterEnum[T comparable](values []T) {
+	set := make(map[any]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	enums[reflect.TypeOf((*T)(nil)).Elem()] = set
+}
+
+func unregisterEnum(t reflect.Type) {
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	delete(enums, t)
+}
+
+func lookupEnum(t reflect.Type) (map[any]struct{}, bool) {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+	set, ok := enums[t]
+	return set, ok
+}
+
+// validateEnum requires one of the values registered with RegisterEnum for the type of the field,
+// or for the element type of a slice field.
+func validateEnum(v reflect.Value, value string) (bool, error) {
+	if len(value) != 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if set, ok := lookupEnum(v.Type()); ok {
+		value, ok := readable(v)
+		if !ok {
+			return false, ValidationError{Err: ErrValidateForUnexportedFields}
+		}
+		if _, ok := set[value.Interface()]; !ok {
+			return false, ValidationError{Err: errors.Errorf("%v is not a valid %s", value.Interface(), v.Type())}
+		}
+		return true, nil
+	}
+	if v.Kind() != reflect.Slice {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	set, ok := lookupEnum(v.Type().Elem())
+	if !ok {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem, ok := readable(v.Index(i))
+		if !ok {
+			return false, ValidationError{Err: ErrValidateForUnexportedFields}
+		}
+		if _, ok := set[elem.Interface()]; !ok {
+			return false, elemError(v, i, errors.Errorf("The %s on position %d is not valid", v.Type().Elem(), i))
+		}
+	}
+	return true, nil
+}
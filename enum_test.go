@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStatus int
+
+const (
+	testActive testStatus = iota + 1
+	testClosed
+)
+
+func (s testStatus) String() string {
+	switch s {
+	case testActive:
+		return "active"
+	case testClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+type testColor string
+
+func TestValidateEnum(t *testing.T) {
+	RegisterEnum([]testStatus{testActive, testClosed})
+	t.Cleanup(func() {
+		unregisterEnum(reflect.TypeOf(testActive))
+		unregisterEnum(reflect.TypeOf(testColor("")))
+	})
+	type ticket struct {
+		Status  testStatus   `validate:"enum:"`
+		History []testStatus `validate:"enum:"`
+		Parent  *testStatus  `validate:"enum:"`
+	}
+	active, unknown := testActive, testStatus(7)
+	tests := []struct {
+		name string
+		in   any
+		err  string
+	}{
+		{name: "valid", in: ticket{Status: testClosed, History: []testStatus{testActive, testClosed}, Parent: &active}},
+		{name: "nil pointer", in: ticket{Status: testActive}},
+		{name: "zero value", in: ticket{}, err: "unknown is not a valid validation.testStatus"},
+		{name: "invalid pointee", in: ticket{Status: testActive, Parent: &unknown}, err: "unknown is not a valid validation.testStatus"},
+		{
+			name: "invalid element",
+			in:   ticket{Status: testActive, History: []testStatus{testActive, 0}},
+			err:  "The validation.testStatus on position 1 is not valid",
+		},
+		{name: "unregistered type", in: struct {
+			Color testColor `validate:"enum:"`
+		}{Color: "red"}, err: ErrInvalidValidatorSyntax.Error()},
+		{name: "argument", in: struct {
+			Status testStatus `validate:"enum:1"`
+		}{Status: testActive}, err: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.in)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	RegisterEnum([]testColor{"red", "green"})
+	assert.NoError(t, Validate(struct {
+		Color testColor `validate:"enum:"`
+	}{Color: "red"}))
+}
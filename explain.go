@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+)
+
+// Explain describes, per tagged field, the rules Validate would apply to v, e.g. "Age: min(18), max(120)",
+// without looking at the field values. Like Validate, it accepts a reflect.Value holding the struct, and
+// describes the fields of its nested structs by their path, e.g. "Address.City: min(1)", including the
+// ones behind nil pointers, but not a struct type again within itself. Malformed tags and unknown validators
+// are reported in the returned ValidationErrors, while the fields with valid tags are still described; a
+// field whose rules are all unknown is left out.
+func Explain(v any) ([]string, error) {
+	vValue, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	var vs ValidationErrors
+	explainStruct(vValue, "", map[reflect.Type]struct{}{vValue.Type(): {}}, &res, &vs)
+	if len(vs) == 0 {
+		return res, nil
+	}
+	return res, vs
+}
+
+// explainStruct adds the descriptions of the fields of vValue and of its nested structs, named after prefix,
+// to res and the problems of their tags to vs. visiting are the struct types on the path to vValue.
+func explainStruct(vValue reflect.Value, prefix string, visiting map[reflect.Type]struct{}, res *[]string, vs *ValidationErrors) {
+	vType := vValue.Type()
+	provided := providedRules(vValue)
+	for i := 0; i < vType.NumField(); i++ {
+		curField := vType.Field(i)
+		name := prefix + curField.Name
+		if description, ok := explainField(curField, name, provided, vs); ok {
+			*res = append(*res, description)
+		}
+		if !curField.IsExported() {
+			continue
+		}
+		nested := vValue.Field(i)
+		if nested.Kind() == reflect.Pointer {
+			if nested.IsNil() {
+				nested = reflect.Zero(nested.Type().Elem())
+			} else {
+				nested = nested.Elem()
+			}
+		}
+		if nested.Kind() != reflect.Struct || isSQLNull(nested.Type()) {
+			continue
+		}
+		if _, ok := visiting[nested.Type()]; ok {
+			continue
+		}
+		visiting[nested.Type()] = struct{}{}
+		explainStruct(nested, name+".", visiting, res, vs)
+		delete(visiting, nested.Type())
+	}
+}
+
+// explainField returns the description of the rules of curField, named name, and whether it has any.
+func explainField(curField reflect.StructField, name string, provided map[string]string, vs *ValidationErrors) (string, bool) {
+	tagValue, ok := curField.Tag.Lookup(defaultTagKey)
+	if rules, isProvided := provided[curField.Name]; isProvided {
+		tagValue, ok = rules, true
+	}
+	if !ok {
+		return "", false
+	} else if !curField.IsExported() {
+		*vs = append(*vs, ValidationError{Err: ErrValidateForUnexportedFields})
+		return "", false
+	}
+	rules, err := parseRules(tagValue)
+	if err != nil {
+		*vs = append(*vs, ValidationError{Err: errors.Wrapf(err, "Field %s", name)})
+		return "", false
+	}
+	descriptions := make([]string, 0, len(rules))
+	for _, r := range rules {
+		if _, ok := modifiers[r.name]; ok {
+			descriptions = append(descriptions, r.name)
+			continue
+		}
+		description, unknown := describeRule(r)
+		for _, unknownName := range unknown {
+			*vs = append(*vs, ValidationError{Err: errors.Errorf("Field %s uses unknown validator %q", name, unknownName), Rule: unknownName})
+		}
+		if len(unknown) == 0 {
+			descriptions = append(descriptions, description)
+		}
+	}
+	if len(descriptions) == 0 {
+		return "", false
+	}
+	return name + ": " + strings.Join(descriptions, ", "), true
+}
+
+// describeRule returns the description of r and the unknown validators it uses.
+func describeRule(r rule) (string, []string) {
+	if len(r.alternatives) == 0 {
+		if r.name == whenRule || r.name == skipIfRule {
+			return r.name + "(" + r.arg + ")", nil
+		}
+		if !isKnownValidator(r.name) {
+			return "", []string{r.name}
+		}
+		return r.name + "(" + r.arg + ")", nil
+	}
+	descriptions := make([]string, 0, len(r.alternatives))
+	var unknown []string
+	for _, alternative := range r.alternatives {
+		description, altUnknown := describeRule(alternative)
+		descriptions = append(descriptions, description)
+		unknown = append(unknown, altUnknown...)
+	}
+	return strings.Join(descriptions, " | "), unknown
+}
+
+func isKnownValidator(name string) bool {
+	if _, ok := lookupValidator(nil, name); ok {
+		return true
+	}
+	_, ok := lookupFieldValidator(name)
+	return ok
+}
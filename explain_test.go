@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	type person struct {
+		Name    string `validate:"notblank:"`
+		Age     int    `validate:"min:18;max:120"`
+		Email   string
+		Country string `validate:"warn;country:alpha2"`
+	}
+	got, err := Explain(person{Age: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Name: notblank()",
+		"Age: min(18), max(120)",
+		"Country: warn, country(alpha2)",
+	}, got)
+
+	got, err = Explain(struct {
+		Age  int    `validate:"min:18;mxa:120"`
+		Name string `validate:"len"`
+		Nick string `validate:"max:8"`
+	}{})
+	assert.Equal(t, []string{"Age: min(18)", "Nick: max(8)"}, got)
+	assert.EqualError(t, err, `Field Age uses unknown validator "mxa"`+
+		"Field Name: "+ErrInvalidValidatorSyntax.Error())
+
+	got, err = Explain(struct {
+		Age  int    `validate:"mxa:120"`
+		Nick string `validate:"max:8"`
+	}{})
+	assert.Equal(t, []string{"Nick: max(8)"}, got)
+	assert.EqualError(t, err, `Field Age uses unknown validator "mxa"`)
+
+	got, err = Explain(reflect.ValueOf(person{}))
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+
+	_, err = Explain(42)
+	assert.ErrorIs(t, err, ErrNotStruct)
+	_, err = Explain(nil)
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
+
+func TestExplainNested(t *testing.T) {
+	type address struct {
+		City string `validate:"min:1"`
+		Zip  string `validate:"zipx:5"`
+	}
+	type node struct {
+		ID   int `validate:"min:1"`
+		Next *node
+	}
+	type order struct {
+		From    address
+		To      *address
+		Head    node
+		Comment string `validate:"max:100"`
+	}
+	got, err := Explain(order{})
+	assert.Equal(t, []string{
+		"From.City: min(1)",
+		"To.City: min(1)",
+		"Head.ID: min(1)",
+		"Comment: max(100)",
+	}, got)
+	assert.EqualError(t, err, `Field From.Zip uses unknown validator "zipx"`+
+		`Field To.Zip uses unknown validator "zipx"`)
+}
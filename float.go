@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// parseFloats parses the bounds with strconv.ParseFloat, reporting false if any of them is malformed or NaN.
+func parseFloats(bounds ...string) ([]float64, bool) {
+	res := make([]float64, 0, len(bounds))
+	for _, bound := range bounds {
+		f, err := strconv.ParseFloat(bound, 64)
+		if err != nil || math.IsNaN(f) {
+			return nil, false
+		}
+		res = append(res, f)
+	}
+	return res, true
+}
+
+// validateFloat checks a float, or each element of a slice of floats, against the optional min and max bounds.
+// NaN and infinite values are rejected as such, as no range can hold NaN.
+func validateFloat(v reflect.Value, min, max *float64) (bool, error) {
+	if isFloatKind(elemKind(v)) {
+		for i := 0; i < v.Len(); i++ {
+			f := v.Index(i).Float()
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return false, elemError(v, i, errors.Errorf("The number on position %d is not a finite number", i))
+			}
+			if min != nil && f < *min {
+				return false, elemError(v, i, errors.Errorf("The number on position %d is less than allowed", i))
+			}
+			if max != nil && f > *max {
+				return false, elemError(v, i, errors.Errorf("The number on position %d is more than allowed", i))
+			}
+		}
+		return true, nil
+	}
+	f := v.Float()
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return false, ValidationError{Err: errors.New("Number is not a finite number")}
+	}
+	if min != nil && f < *min {
+		return false, ValidationError{Err: errors.New("Number is less than allowed")}
+	}
+	if max != nil && f > *max {
+		return false, ValidationError{Err: errors.New("Number is more than allowed")}
+	}
+	return true, nil
+}
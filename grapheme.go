@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode"
+)
+
+const (
+	zeroWidthJoiner = '\u200d'
+	// regionalIndicatorA to regionalIndicatorZ are the runes pairing up into flag emoji.
+	regionalIndicatorA = '\U0001F1E6'
+	regionalIndicatorZ = '\U0001F1FF'
+)
+
+// extendsGrapheme reports whether r continues the grapheme cluster of the rune before it.
+func extendsGrapheme(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= '\ufe00' && r <= '\ufe0f': // variation selectors
+		return true
+	case r >= '\U0001F3FB' && r <= '\U0001F3FF': // emoji skin tone modifiers
+		return true
+	case r >= '\U000E0020' && r <= '\U000E007F': // emoji tag sequences
+		return true
+	default:
+		return r == zeroWidthJoiner
+	}
+}
+
+// countGraphemes counts the user-perceived characters of s. It is a dependency-free approximation of
+// the Unicode segmentation rules, which handles combining marks, variation selectors, emoji modifiers,
+// ZWJ sequences, flags and CRLF, but not e.g. Hangul syllables made of separate jamos.
+func countGraphemes(s string) int {
+	count := 0
+	prev := rune(-1)
+	pairedFlag := false
+	for _, r := range s {
+		isFlag := r >= regionalIndicatorA && r <= regionalIndicatorZ
+		switch {
+		case prev == -1:
+			count++
+		case extendsGrapheme(r), prev == zeroWidthJoiner, prev == '\r' && r == '\n':
+		case isFlag && prev >= regionalIndicatorA && prev <= regionalIndicatorZ && !pairedFlag:
+			pairedFlag = true
+			prev = r
+			continue
+		default:
+			count++
+		}
+		pairedFlag = false
+		prev = r
+	}
+	return count
+}
+
+// checkGraphemes applies the bound parsed from value to the grapheme count of the string v, or of
+// each of its elements.
+func checkGraphemes(v reflect.Value, value string, ok func(count, bound int) bool, message string) (bool, error) {
+	bound, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return matchStrings(v, func(s string) bool {
+		return ok(countGraphemes(s), bound)
+	}, fmt.Sprintf(message, bound))
+}
+
+// validateGraphLen requires an exact number of grapheme clusters, e.g. "graphlen:1" for a single emoji.
+func validateGraphLen(v reflect.Value, value string) (bool, error) {
+	return checkGraphemes(v, value, func(count, bound int) bool { return count == bound }, "String doesn't have %d characters")
+}
+
+// validateGraphMin bounds the number of grapheme clusters, unlike min which counts bytes.
+func validateGraphMin(v reflect.Value, value string) (bool, error) {
+	return checkGraphemes(v, value, func(count, bound int) bool { return count >= bound }, "String has fewer than %d characters")
+}
+
+// validateGraphMax is the upper-bound counterpart of validateGraphMin, e.g. "graphmax:280".
+func validateGraphMax(v reflect.Value, value string) (bool, error) {
+	return checkGraphemes(v, value, func(count, bound int) bool { return count <= bound }, "String has more than %d characters")
+}
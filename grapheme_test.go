@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountGraphemes(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{s: "", want: 0},
+		{s: "abc", want: 3},
+		{s: "e\u0301te\u0301", want: 3}, // e + combining acute accent
+		{s: "👍🏽", want: 1},              // thumbs up + skin tone
+		{s: "👨‍👩‍👧‍👦", want: 1},         // family ZWJ sequence
+		{s: "🏳️‍🌈", want: 1},            // flag + variation selector + ZWJ + rainbow
+		{s: "🇫🇷🇩🇪", want: 2},            // two flags
+		{s: "🇫🇷🇩", want: 2},             // a flag and a lone regional indicator
+		{s: "a\r\nb", want: 3},
+		{s: "❤️ok", want: 3},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, countGraphemes(tt.s), tt.s)
+	}
+}
+
+func TestValidateGraphemes(t *testing.T) {
+	type profile struct {
+		Avatar string   `validate:"graphlen:1"`
+		Bio    string   `validate:"graphmax:5"`
+		Name   string   `validate:"graphmin:2"`
+		Tags   []string `validate:"graphmax:2"`
+	}
+	assert.NoError(t, Validate(profile{Avatar: "👨‍👩‍👧‍👦", Bio: "👍🏽👍🏽👍🏽👍🏽👍🏽", Name: "🇫🇷🇩🇪", Tags: []string{"🏳️‍🌈!", "ok"}}))
+	assert.EqualError(t, Validate(profile{Avatar: "👍🏽👍🏽", Bio: "👍🏽👍🏽👍🏽👍🏽👍🏽👍🏽", Name: "é", Tags: []string{"ok", "abc"}}),
+		"String doesn't have 1 characters"+"String has more than 5 characters"+"String has fewer than 2 characters"+
+			"The string on position 1 is not allowed: String has more than 2 characters")
+	assert.EqualError(t, Validate(struct {
+		A string `validate:"graphmax:x"`
+		B int    `validate:"graphmin:1"`
+	}{}), ErrInvalidValidatorSyntax.Error()+"Field of type int: unsupported field type")
+}
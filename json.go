@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// compareJSONNumber compares n with bound as integers if both are ones, and as floats otherwise.
+// It reports false if either of them is not a number.
+func compareJSONNumber(n json.Number, bound string) (int, bool) {
+	if i, err := n.Int64(); err == nil {
+		if b, err := strconv.ParseInt(bound, 10, 64); err == nil {
+			switch {
+			case i < b:
+				return -1, true
+			case i > b:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0, false
+	}
+	b, err := strconv.ParseFloat(bound, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case f < b:
+		return -1, true
+	case f > b:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// jsonBoundsReversed reports whether the min and max bounds of a json.Number are both numbers, in descending order.
+func jsonBoundsReversed(min, max string) bool {
+	c, ok := compareJSONNumber(json.Number(min), max)
+	return ok && c > 0
+}
+
+// validateJSONNumber checks a json.Number value against the optional min and max bounds. A value which is
+// not a number is a violation, while a bound which is not one is ErrInvalidValidatorSyntax.
+func validateJSONNumber(v reflect.Value, min, max *string) (bool, error) {
+	n := json.Number(v.String())
+	if _, err := n.Float64(); err != nil {
+		return false, ValidationError{Err: errors.Errorf("%q is not a valid number", v.String())}
+	}
+	if min != nil {
+		c, ok := compareJSONNumber(n, *min)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		} else if c < 0 {
+			return false, ValidationError{Err: errors.New("Number is less than allowed")}
+		}
+	}
+	if max != nil {
+		c, ok := compareJSONNumber(n, *max)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		} else if c > 0 {
+			return false, ValidationError{Err: errors.New("Number is more than allowed")}
+		}
+	}
+	return true, nil
+}
+
+// hasJSONOmitempty reports whether the `json` tag of a field has the omitempty option.
+func hasJSONOmitempty(tag reflect.StructTag) bool {
+	jsonTag, ok := tag.Lookup("json")
+	if !ok {
+		return false
+	}
+	_, options, _ := strings.Cut(jsonTag, ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"net/netip"
+	"reflect"
+)
+
+var netipAddrType = reflect.TypeOf(netip.Addr{})
+
+// parseAddrs parses the address bounds, reporting false if any of them is malformed or if they mix IPv4 and IPv6.
+func parseAddrs(bounds ...string) ([]netip.Addr, bool) {
+	res := make([]netip.Addr, 0, len(bounds))
+	for _, bound := range bounds {
+		addr, err := netip.ParseAddr(bound)
+		if err != nil || (len(res) != 0 && addr.Is4() != res[0].Is4()) {
+			return nil, false
+		}
+		res = append(res, addr)
+	}
+	return res, true
+}
+
+// validateAddr checks a netip.Addr value against the optional min and max bounds. Comparing
+// addresses of different families is meaningless, so it is reported as ErrInvalidValidatorSyntax.
+func validateAddr(v reflect.Value, min, max *netip.Addr) (bool, error) {
+	if !v.CanInterface() {
+		return false, ValidationError{Err: ErrValidateForUnexportedFields}
+	}
+	addr := v.Interface().(netip.Addr)
+	if !addr.IsValid() {
+		return false, ValidationError{Err: errors.New("Field is not a valid IP address")}
+	}
+	for _, bound := range []*netip.Addr{min, max} {
+		if bound != nil && bound.Is4() != addr.Is4() {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+	}
+	if min != nil && addr.Compare(*min) < 0 {
+		return false, ValidationError{Err: errors.Errorf("Address %s is less than allowed %s", addr, *min)}
+	}
+	if max != nil && addr.Compare(*max) > 0 {
+		return false, ValidationError{Err: errors.Errorf("Address %s is more than allowed %s", addr, *max)}
+	}
+	return true, nil
+}
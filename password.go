@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// passwordClasses are the character classes a password policy can require, with their descriptions.
+var passwordClasses = map[string]struct {
+	is          func(rune) bool
+	description string
+}{
+	"upper":   {is: unicode.IsUpper, description: "uppercase letter"},
+	"lower":   {is: unicode.IsLower, description: "lowercase letter"},
+	"digit":   {is: unicode.IsDigit, description: "digit"},
+	"special": {is: func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) }, description: "special character"},
+}
+
+// passwordRequirement is a single key=count item of a password policy.
+type passwordRequirement struct {
+	key   string
+	count int
+}
+
+// parsePasswordPolicy parses a policy like "min=8,upper=1", keeping the order of its requirements.
+func parsePasswordPolicy(policy string) ([]passwordRequirement, bool) {
+	if len(policy) == 0 {
+		return nil, false
+	}
+	items := strings.Split(policy, ",")
+	res := make([]passwordRequirement, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, false
+		}
+		if _, isClass := passwordClasses[key]; !isClass && key != "min" {
+			return nil, false
+		}
+		if _, dup := seen[key]; dup {
+			return nil, false
+		}
+		seen[key] = struct{}{}
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return nil, false
+		}
+		res = append(res, passwordRequirement{key: key, count: count})
+	}
+	return res, true
+}
+
+// validatePassword checks a string against a policy like "password:min=8,upper=1,lower=1,digit=1,special=1"
+// giving the minimal length and the minimal number of characters of each class, and reports the first
+// requirement of the policy the string doesn't meet.
+func validatePassword(v reflect.Value, value string) (bool, error) {
+	requirements, ok := parsePasswordPolicy(value)
+	if !ok {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if v.Kind() != reflect.String {
+		return false, unsupportedType(v)
+	}
+	password := v.String()
+	for _, req := range requirements {
+		if req.key == "min" {
+			if utf8.RuneCountInString(password) < req.count {
+				return false, ValidationError{Err: errors.Errorf("Password must be at least %d characters long", req.count)}
+			}
+			continue
+		}
+		class := passwordClasses[req.key]
+		found := 0
+		for _, r := range password {
+			if class.is(r) {
+				found++
+			}
+		}
+		if found < req.count {
+			return false, ValidationError{Err: errors.Errorf("Password must contain at least %d %s(s)", req.count, class.description)}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePassword(t *testing.T) {
+	type signup struct {
+		Password string `validate:"password:min=8,upper=1,lower=1,digit=1,special=1"`
+	}
+	tests := []struct {
+		name     string
+		password string
+		wantErr  string
+	}{
+		{name: "strong", password: "Passw0rd!"},
+		{name: "unicode", password: "Пароль-2024"},
+		{name: "short", password: "Pa0!", wantErr: "Password must be at least 8 characters long"},
+		{name: "no upper", password: "passw0rd!", wantErr: "Password must contain at least 1 uppercase letter(s)"},
+		{name: "no lower", password: "PASSW0RD!", wantErr: "Password must contain at least 1 lowercase letter(s)"},
+		{name: "no digit", password: "Password!", wantErr: "Password must contain at least 1 digit(s)"},
+		{name: "no special", password: "Passw0rd", wantErr: "Password must contain at least 1 special character(s)"},
+		{name: "first unmet", password: "pass", wantErr: "Password must be at least 8 characters long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(signup{Password: tt.password})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	assert.EqualError(t, Validate(struct {
+		P string `validate:"password:digit=2,min=4"`
+	}{P: "ab1"}), "Password must contain at least 2 digit(s)")
+	for _, policy := range []string{"", "min", "min=x", "min=-1", "length=8", "min=8,min=9"} {
+		err := ValidateWithResolver(struct {
+			P string `validate:"password:${policy}"`
+		}{P: "Passw0rd!"}, func(string) (string, bool) { return policy, true })
+		assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error(), policy)
+	}
+}
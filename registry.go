@@ -0,0 +1,171 @@
+package validation
+
+import (
+	"bufio"
+	"context"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// validatorsMu guards validators, fieldValidators and customValidators against registrations.
+var validatorsMu sync.RWMutex
+
+// customValidators are the names registered with RegisterValidator or RegisterFieldValidator.
+var customValidators = make(map[string]struct{})
+
+// RegisterValidator makes fn available to all validations under name, replacing the validator
+// previously registered with that name, including a built-in one.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	delete(fieldValidators, name)
+	validators[name] = fn
+	customValidators[name] = struct{}{}
+}
+
+// RegisterFieldValidator is like RegisterValidator, but for validators that need more than the
+// value of the field, e.g. its sibling fields or its other tags.
+func RegisterFieldValidator(name string, fn FieldValidatorFunc) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	delete(validators, name)
+	fieldValidators[name] = fn
+	customValidators[name] = struct{}{}
+}
+
+func unregisterValidator(name string) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	delete(validators, name)
+	delete(fieldValidators, name)
+	delete(customValidators, name)
+}
+
+// RegisteredValidators returns the sorted names of all the validators available to tags,
+// built-in and registered ones alike.
+func RegisteredValidators() []string {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	names := make([]string, 0, len(validators)+len(fieldValidators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	for name := range fieldValidators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsBuiltin reports whether name is a validator provided by the package that has not been
+// replaced with RegisterValidator or RegisterFieldValidator.
+func IsBuiltin(name string) bool {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	if _, ok := customValidators[name]; ok {
+		return false
+	}
+	_, ok := validators[name]
+	if !ok {
+		_, ok = fieldValidators[name]
+	}
+	return ok
+}
+
+// contextValidatorsKey is the context key of the map[string]ValidatorFunc set by WithValidator.
+type contextValidatorsKey struct{}
+
+// WithValidator returns a copy of ctx in which fn is available under name to ValidateContext.
+// Context validators take precedence over the ones registered with RegisterValidator.
+func WithValidator(ctx context.Context, name string, fn ValidatorFunc) context.Context {
+	parent, _ := ctx.Value(contextValidatorsKey{}).(map[string]ValidatorFunc)
+	scoped := make(map[string]ValidatorFunc, len(parent)+1)
+	for k, v := range parent {
+		scoped[k] = v
+	}
+	scoped[name] = fn
+	return context.WithValue(ctx, contextValidatorsKey{}, scoped)
+}
+
+// ValidateContext is like Validate, but uses the validators added to ctx by WithValidator.
+// It stops with the context error once ctx is done.
+func ValidateContext(ctx context.Context, v any) error {
+	return ValidateWithOptions(v, Options{ctx: ctx})
+}
+
+// lookupValidator finds the validator registered under name, looking into ctx first.
+func lookupValidator(ctx context.Context, name string) (ValidatorFunc, bool) {
+	if ctx != nil {
+		if scoped, ok := ctx.Value(contextValidatorsKey{}).(map[string]ValidatorFunc); ok {
+			if fn, ok := scoped[name]; ok {
+				return fn, true
+			}
+		}
+	}
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	fn, ok := validators[name]
+	return fn, ok
+}
+
+func lookupFieldValidator(name string) (FieldValidatorFunc, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	fn, ok := fieldValidators[name]
+	return fn, ok
+}
+
+// valueSet is a set of allowed values registered with RegisterSetFromReader.
+type valueSet struct {
+	values []string
+	index  map[string]struct{}
+}
+
+var (
+	setsMu sync.RWMutex
+	sets   = make(map[string]valueSet)
+)
+
+// RegisterSetFromReader reads newline-separated values from r and stores them under name, so that tags can
+// allow them with "in:@name", e.g. a list of reserved user names loaded at startup. The values are trimmed,
+// and blank lines are skipped. It replaces the set previously registered with that name, unless r fails.
+//
+// An in rule starting with "@" always names a set, so a tag such as "in:@home,@work" is a syntax error
+// unless a set of that name is registered; quote the tokens to allow them literally: "in:'@home','@work'".
+func RegisterSetFromReader(name string, r io.Reader) error {
+	set := valueSet{index: make(map[string]struct{})}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		value := strings.TrimSpace(scanner.Text())
+		if value == "" {
+			continue
+		}
+		if _, ok := set.index[value]; !ok {
+			set.values = append(set.values, value)
+			set.index[value] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "Can't read the values of set %s", name)
+	}
+	setsMu.Lock()
+	defer setsMu.Unlock()
+	sets[name] = set
+	return nil
+}
+
+func unregisterSet(name string) {
+	setsMu.Lock()
+	defer setsMu.Unlock()
+	delete(sets, name)
+}
+
+func lookupSet(name string) (valueSet, bool) {
+	setsMu.RLock()
+	defer setsMu.RUnlock()
+	set, ok := sets[name]
+	return set, ok
+}
@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateContext(t *testing.T) {
+	tenantPrefix := func(v reflect.Value, arg string) (bool, error) {
+		if !strings.HasPrefix(v.String(), arg) {
+			return false, ValidationError{Err: fmt.Errorf("String must start with %q", arg)}
+		}
+		return true, nil
+	}
+	type order struct {
+		ID   string `validate:"tenant:acme-"`
+		Note string `validate:"max:5"`
+	}
+
+	ctx := WithValidator(context.Background(), "tenant", tenantPrefix)
+	assert.NoError(t, ValidateContext(ctx, order{ID: "acme-1"}))
+	assert.EqualError(t, ValidateContext(ctx, order{ID: "other-1"}), `String must start with "acme-"`)
+
+	// without the context the validator is unknown
+	assert.EqualError(t, Validate(order{ID: "acme-1"}), "Unexpected validator option")
+	assert.EqualError(t, ValidateContext(context.Background(), order{ID: "acme-1"}), "Unexpected validator option")
+
+	// context validators take precedence over the global ones
+	strict := WithValidator(ctx, "max", func(v reflect.Value, arg string) (bool, error) {
+		return v.Len() == 0, nil
+	})
+	assert.NoError(t, ValidateContext(ctx, order{ID: "acme-1", Note: "hi"}))
+	assert.EqualError(t, ValidateContext(strict, order{ID: "acme-1", Note: "hi"}), "Field doesn't satisfy the max rule")
+	assert.NoError(t, Validate(struct {
+		Note string `validate:"max:5"`
+	}{Note: "hi"}))
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, ValidateContext(cancelled, order{ID: "acme-1"}), context.Canceled)
+}
+
+func TestRegisterValidator(t *testing.T) {
+	RegisterValidator("even", func(v reflect.Value, arg string) (bool, error) {
+		if v.Int()%2 != 0 {
+			return false, ValidationError{Err: errors.New("Integer is odd")}
+		}
+		return true, nil
+	})
+	defer unregisterValidator("even")
+	RegisterValidator("broken", func(v reflect.Value, arg string) (bool, error) {
+		return false, errors.New("backend unavailable")
+	})
+	defer unregisterValidator("broken")
+
+	assert.NoError(t, Validate(struct {
+		N int `validate:"even:"`
+	}{N: 4}))
+	assert.EqualError(t, Validate(struct {
+		N int `validate:"even:;min:10"`
+	}{N: 3}), "Integer is odd"+"Integer is less than allowed")
+	assert.EqualError(t, Validate(struct {
+		N int `validate:"broken:"`
+	}{}), "backend unavailable")
+}
+
+func TestRegisteredValidators(t *testing.T) {
+	RegisterValidator("odd", func(v reflect.Value, arg string) (bool, error) {
+		return v.Int()%2 != 0, nil
+	})
+	defer unregisterValidator("odd")
+
+	names := RegisteredValidators()
+	assert.True(t, sort.StringsAreSorted(names))
+	assert.Subset(t, names, []string{"len", "in", "min", "max", "between", "maxlenfield", "odd"})
+	assert.True(t, IsBuiltin("min"))
+	assert.True(t, IsBuiltin("maxlenfield"))
+	assert.False(t, IsBuiltin("odd"))
+	assert.False(t, IsBuiltin("nope"))
+}
+
+func ExampleRegisterFieldValidator() {
+	// "confirms:Password" requires the field to repeat the value of the Password field
+	RegisterFieldValidator("confirms", func(fc FieldContext) (bool, error) {
+		other := fc.Parent.FieldByName(fc.Arg)
+		if !other.IsValid() {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if fc.Value.String() != other.String() {
+			return false, ValidationError{Err: fmt.Errorf("%s doesn't match %s (%s)", fc.Name, fc.Arg, fc.Tag.Get("label"))}
+		}
+		return true, nil
+	})
+	defer unregisterValidator("confirms")
+
+	type signup struct {
+		Password string `validate:"min:8"`
+		Repeat   string `validate:"confirms:Password" label:"repeat the password"`
+	}
+	fmt.Println(Validate(signup{Password: "correct horse", Repeat: "correct horse"}))
+	fmt.Println(Validate(signup{Password: "correct horse", Repeat: "battery staple"}))
+	// Output:
+	// <nil>
+	// Repeat doesn't match Password (repeat the password)
+}
+
+func TestOnValidatorRun(t *testing.T) {
+	RegisterValidator("slow", func(v reflect.Value, arg string) (bool, error) {
+		time.Sleep(time.Millisecond)
+		return true, nil
+	})
+	defer unregisterValidator("slow")
+
+	var names []string
+	var slowest time.Duration
+	opts := Options{OnValidatorRun: func(name string, d time.Duration) {
+		names = append(names, name)
+		if name == "slow" {
+			slowest = d
+		}
+	}}
+	err := ValidateWithOptions(struct {
+		A string `validate:"min:1;max:3"`
+		B int    `validate:"slow:"`
+		C string `validate:"uuid:|len:2"`
+		D string `validate:"unknown:"`
+	}{A: "abcd", C: "ab"}, opts)
+	assert.EqualError(t, err, "String length is more than allowed"+"Unexpected validator option")
+	assert.Equal(t, []string{"min", "max", "slow", "uuid", "len"}, names)
+	assert.GreaterOrEqual(t, slowest, time.Millisecond)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk error")
+}
+
+func TestRegisterSetFromReader(t *testing.T) {
+	assert.NoError(t, RegisterSetFromReader("reserved", strings.NewReader("admin\n  root \n\n\t\nsupport\nadmin\n")))
+	assert.NoError(t, RegisterSetFromReader("ports", strings.NewReader("80\n443\n8000-8080\n")))
+	t.Cleanup(func() {
+		unregisterSet("reserved")
+		unregisterSet("ports")
+	})
+	type signup struct {
+		Username string   `validate:"in:!@reserved"`
+		Role     string   `validate:"in:@reserved"`
+		Ports    []int    `validate:"in:@ports"`
+		Aliases  []string `validate:"in:!@reserved"`
+	}
+	assert.NoError(t, Validate(signup{Username: "bob", Role: "root", Ports: []int{443, 8042}, Aliases: []string{"bobby"}}))
+	assert.EqualError(t, Validate(signup{Username: "admin", Role: "bob", Ports: []int{22}, Aliases: []string{"b", "support"}}),
+		"Field value isn't allowed"+
+			"Field value isn't allowed"+
+			"The integer on position 0 is less than allowed"+
+			"The string on position 1 is not allowed")
+
+	assert.EqualError(t, Validate(struct {
+		Role string `validate:"in:@missing"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+
+	// the @ of an unquoted token names a set, so literal @ values have to be quoted
+	assert.EqualError(t, Validate(struct {
+		Place string `validate:"in:@home,@work"`
+	}{Place: "@home"}), ErrInvalidValidatorSyntax.Error())
+	type location struct {
+		Place string `validate:"in:'@home','@work'"`
+	}
+	assert.NoError(t, Validate(location{Place: "@work"}))
+	assert.EqualError(t, Validate(location{Place: "home"}), "Field value isn't allowed")
+
+	err := RegisterSetFromReader("reserved", failingReader{})
+	assert.EqualError(t, err, "Can't read the values of set reserved: disk error")
+	assert.NoError(t, Validate(signup{Username: "bob", Role: "root"}), "a failed read keeps the previous set")
+}
@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Severity tells how serious a violation is; only the SeverityError ones make a validation fail.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "Severity(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Result holds the violations found in a struct, split by severity.
+type Result struct {
+	errors   ValidationErrors
+	warnings ValidationErrors
+	infos    ValidationErrors
+	// checked are the names of the fields with rules, see ValidateAndReport
+	checked []string
+}
+
+// Errors returns the violations that make the validation fail.
+func (r Result) Errors() ValidationErrors {
+	return r.errors
+}
+
+// Warnings returns the violations of fields marked with the warn modifier.
+func (r Result) Warnings() ValidationErrors {
+	return r.warnings
+}
+
+// Infos returns the violations of fields marked with the info modifier.
+func (r Result) Infos() ValidationErrors {
+	return r.infos
+}
+
+// add sorts vs into r, keeping at most maxErrors errors if maxErrors > 0.
+// It reports whether that limit is reached.
+func (r *Result) add(vs ValidationErrors, maxErrors int) bool {
+	for _, ve := range vs {
+		switch ve.Severity {
+		case SeverityWarning:
+			r.warnings = append(r.warnings, ve)
+			continue
+		case SeverityInfo:
+			r.infos = append(r.infos, ve)
+			continue
+		}
+		r.errors = append(r.errors, ve)
+		if maxErrors > 0 && len(r.errors) >= maxErrors {
+			return true
+		}
+	}
+	return false
+}
+
+// err returns the errors of r as ValidationErrors, or nil if there are none.
+func (r Result) err() error {
+	if len(r.errors) == 0 {
+		return nil
+	}
+	return r.errors
+}
+
+// CheckWithWarnings is like Validate, but also returns the Result holding the warnings and infos.
+// Warnings and infos alone do not make it return an error.
+func CheckWithWarnings(v any) (Result, error) {
+	res, err := check(v, Options{})
+	if err != nil {
+		return res, err
+	}
+	return res, res.err()
+}
+
+// Pos is a position in a source document, e.g. the YAML file a struct was decoded from.
+type Pos struct {
+	Line   int
+	Column int
+}
+
+// FieldViolation is a violation of the rules of a field, in a form suitable for reporting.
+type FieldViolation struct {
+	// Field is the name of the struct field, or its alias from the `name` tag, followed by the
+	// Path of the offending element if any, e.g. "Matrix[1][2]".
+	Field string
+	// Description is the message of the violation.
+	Description string
+	// Rule is the name of the failed validator, as in ValidationError.
+	Rule string
+	// Severity is the severity of the violation.
+	Severity Severity
+	// Pos is the position of the field given to ValidateWithPositions, if any.
+	Pos Pos
+}
+
+// ValidateWithPositions is like Validate, but returns the violations as FieldViolations carrying
+// the position found for their field in positions. The returned error is only non-nil if v can't
+// be validated at all, e.g. ErrNotStruct.
+func ValidateWithPositions(v any, positions map[string]Pos) ([]FieldViolation, error) {
+	res, err := check(v, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return fieldViolations(res.errors, positions), nil
+}
+
+// ValidateFieldViolations is like ValidateWithPositions without positions, in a form that maps directly
+// to google.rpc.BadRequest field violations. If v can't be validated at all, the error is returned as a
+// single violation with an empty Field, i.e. a violation of the whole request.
+func ValidateFieldViolations(v any) []FieldViolation {
+	res, err := ValidateWithPositions(v, nil)
+	if err != nil {
+		return []FieldViolation{{Description: err.Error()}}
+	}
+	return res
+}
+
+// Summary validates v and describes the result in one line for logs, e.g. "3 field(s) failed: Name, Age, Email",
+// naming each failed field once, in declaration order, by its alias if it has one. It returns an empty string
+// if v is valid, and the error message if v can't be validated at all.
+func Summary(v any) string {
+	res, err := check(v, Options{})
+	if err != nil {
+		return err.Error()
+	}
+	var names []string
+	seen := make(map[string]struct{})
+	for _, ve := range res.errors {
+		if _, ok := seen[ve.Field]; ok {
+			continue
+		}
+		seen[ve.Field] = struct{}{}
+		names = append(names, ve.displayName())
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return strconv.Itoa(len(names)) + " field(s) failed: " + strings.Join(names, ", ")
+}
+
+// ValidateAndReport validates v and returns the names of its fields with rules that passed and of the ones
+// that failed, in declaration order, e.g. to highlight the valid fields of a form. Warnings and infos don't
+// make a field fail, and the fields without rules are in neither list. The error is only non-nil if v can't
+// be validated at all.
+func ValidateAndReport(v any) (passed []string, failed []string, err error) {
+	res, err := check(v, Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+	failedSet := make(map[string]struct{}, len(res.errors))
+	for _, ve := range res.errors {
+		failedSet[ve.Field] = struct{}{}
+	}
+	for _, name := range res.checked {
+		if _, ok := failedSet[name]; ok {
+			failed = append(failed, name)
+		} else {
+			passed = append(passed, name)
+		}
+	}
+	return passed, failed, nil
+}
+
+func fieldViolations(vs ValidationErrors, positions map[string]Pos) []FieldViolation {
+	res := make([]FieldViolation, 0, len(vs))
+	for _, ve := range vs {
+		res = append(res, FieldViolation{
+			Field:       ve.displayName() + ve.Path,
+			Description: ve.Error(),
+			Rule:        ve.Rule,
+			Severity:    ve.Severity,
+			Pos:         positions[ve.Field],
+		})
+	}
+	return res
+}
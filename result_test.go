@@ -0,0 +1,216 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWithWarnings(t *testing.T) {
+	type plan struct {
+		Tier    string `validate:"warn;in:free,pro"`
+		Seats   int    `validate:"min:1"`
+		Region  string `validate:"in:eu,us;warn"`
+		Comment string `validate:"max:3"`
+	}
+
+	res, err := CheckWithWarnings(plan{Tier: "legacy", Seats: 1, Region: "eu"})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Errors())
+	assert.EqualError(t, res.Warnings(), "Field value isn't allowed")
+	assert.NoError(t, Validate(plan{Tier: "legacy", Seats: 1, Region: "eu"}))
+
+	res, err = CheckWithWarnings(plan{Tier: "legacy", Seats: 0, Region: "asia", Comment: "long"})
+	assert.EqualError(t, err, "Integer is less than allowed"+"String length is more than allowed")
+	assert.Equal(t, err, res.Errors())
+	assert.Len(t, res.Warnings(), 2)
+	assert.EqualError(t, Validate(plan{Tier: "legacy", Seats: 0, Region: "asia", Comment: "long"}),
+		"Integer is less than allowed"+"String length is more than allowed")
+
+	res, err = CheckWithWarnings(plan{Tier: "pro", Seats: 3, Region: "us"})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Errors())
+	assert.Empty(t, res.Warnings())
+
+	_, err = CheckWithWarnings("plan")
+	assert.ErrorIs(t, err, ErrNotStruct)
+
+	err = Validate(struct {
+		F string `validate:"wrn;in:a"`
+	}{})
+	assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateWithPositions(t *testing.T) {
+	type config struct {
+		Name    string `validate:"min:3"`
+		Port    int    `validate:"between:1,65535"`
+		Mode    string `validate:"in:dev,prod"`
+		private string `validate:"len:1"`
+	}
+	positions := map[string]Pos{
+		"Name": {Line: 1, Column: 7},
+		"Port": {Line: 2, Column: 7},
+	}
+	got, err := ValidateWithPositions(config{Name: "x", Port: 80, Mode: "test"}, positions)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldViolation{
+		{Field: "Name", Description: "String length is less than allowed", Rule: "min", Pos: Pos{Line: 1, Column: 7}},
+		{Field: "Mode", Description: "Field value isn't allowed", Rule: "in"},
+		{Field: "private", Description: ErrValidateForUnexportedFields.Error()},
+	}, got)
+
+	got, err = ValidateWithPositions(struct{ Port int }{}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = ValidateWithPositions([]int{}, positions)
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
+
+func TestResultSeverities(t *testing.T) {
+	type profile struct {
+		Name     string `validate:"min:2"`
+		Theme    string `validate:"warn;in:light,dark"`
+		Timezone string `validate:"info;in:UTC"`
+		Locale   string `validate:"info;warn;len:5"`
+	}
+
+	res, err := CheckWithWarnings(profile{Name: "al", Theme: "dark", Timezone: "UTC", Locale: "en_US"})
+	assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error())
+	assert.Len(t, res.Errors(), 1)
+
+	type settings struct {
+		Name     string `validate:"min:2"`
+		Theme    string `validate:"warn;in:light,dark"`
+		Timezone string `validate:"info;in:UTC"`
+	}
+	res, err = CheckWithWarnings(settings{Name: "al", Theme: "neon", Timezone: "CET"})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Errors())
+	assert.Len(t, res.Warnings(), 1)
+	assert.Equal(t, SeverityWarning, res.Warnings()[0].Severity)
+	assert.Equal(t, "Theme", res.Warnings()[0].Field)
+	assert.Len(t, res.Infos(), 1)
+	assert.Equal(t, SeverityInfo, res.Infos()[0].Severity)
+	assert.Equal(t, "Timezone", res.Infos()[0].Field)
+	assert.NoError(t, Validate(settings{Name: "al", Theme: "neon", Timezone: "CET"}))
+
+	res, err = CheckWithWarnings(settings{Name: "a", Theme: "neon", Timezone: "CET"})
+	assert.EqualError(t, err, "String length is less than allowed")
+	assert.Equal(t, SeverityError, res.Errors()[0].Severity)
+	assert.Len(t, res.Warnings(), 1)
+	assert.Len(t, res.Infos(), 1)
+
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "info", SeverityInfo.String())
+	assert.Equal(t, "Severity(7)", Severity(7).String())
+}
+
+func TestFieldViolationAlias(t *testing.T) {
+	type signup struct {
+		FirstName string `validate:"min:3" name:"First name"`
+		LastName  string `validate:"min:3"`
+		Age       int    `validate:"mni:18" name:"Age"`
+	}
+	got, err := ValidateWithPositions(signup{FirstName: "Al", LastName: "Li"}, map[string]Pos{"FirstName": {Line: 3}})
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldViolation{
+		{Field: "First name", Description: "String length is less than allowed", Rule: "min", Pos: Pos{Line: 3}},
+		{Field: "LastName", Description: "String length is less than allowed", Rule: "min"},
+		{Field: "Age", Description: "Unexpected validator option", Rule: "mni"},
+	}, got)
+
+	type parent struct {
+		Child signup
+	}
+	got, err = ValidateWithPositions(parent{Child: signup{FirstName: "Al", LastName: "Lee"}}, map[string]Pos{"Child.FirstName": {Line: 7}})
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldViolation{
+		{Field: "Child.First name", Description: "String length is less than allowed", Rule: "min", Pos: Pos{Line: 7}},
+		{Field: "Child.Age", Description: "Unexpected validator option", Rule: "mni"},
+	}, got)
+
+	err = Validate(signup{FirstName: "Al", LastName: "Lee"})
+	assert.Equal(t, "FirstName", err.(ValidationErrors)[0].Field)
+}
+
+func TestValidateFieldViolations(t *testing.T) {
+	type createUser struct {
+		Email string `validate:"regexp:@" name:"email"`
+		Age   int    `validate:"min:18" name:"age"`
+		Role  string `validate:"in:admin,user;warn"`
+	}
+	assert.Empty(t, ValidateFieldViolations(createUser{Email: "a@b.c", Age: 18, Role: "admin"}))
+	assert.Equal(t, []FieldViolation{
+		{Field: "email", Description: `String doesn't match "@"`, Rule: "regexp"},
+		{Field: "age", Description: "Integer is less than allowed", Rule: "min"},
+	}, ValidateFieldViolations(createUser{Email: "ab", Age: 17, Role: "root"}))
+	assert.Equal(t, []FieldViolation{{Description: ErrNotStruct.Error()}}, ValidateFieldViolations(42))
+
+	type address struct {
+		City string `validate:"min:2" name:"City name"`
+	}
+	type order struct {
+		Email   string `validate:"regexp:@" name:"email"`
+		Billing address
+		Home    *address `name:"Home address"`
+	}
+	assert.Equal(t, []FieldViolation{
+		{Field: "Billing.City name", Description: "String length is less than allowed", Rule: "min"},
+		{Field: "Home.City name", Description: "String length is less than allowed", Rule: "min"},
+	}, ValidateFieldViolations(order{Email: "a@b", Home: &address{}}))
+}
+
+func TestSummary(t *testing.T) {
+	type signup struct {
+		Name     string   `validate:"min:3;max:10"`
+		Age      int      `validate:"min:18"`
+		Nickname string   `validate:"min:3;warn"`
+		Email    string   `validate:"regexp:@" name:"E-mail"`
+		Tags     []string `validate:"dive;min:2"`
+	}
+	assert.Equal(t, "", Summary(signup{Name: "Bob", Age: 18, Email: "bob@example.com"}))
+	assert.Equal(t, "3 field(s) failed: Name, Age, E-mail",
+		Summary(signup{Name: "Al", Age: 17, Nickname: "x", Email: "bob"}))
+	assert.Equal(t, "2 field(s) failed: Age, Tags",
+		Summary(signup{Name: "Bob", Email: "a@b", Tags: []string{"a", "b"}}))
+	assert.Equal(t, ErrNotStruct.Error(), Summary(42))
+
+	type address struct {
+		City string `validate:"min:2" name:"City"`
+	}
+	type shipment struct {
+		From address
+		To   address
+		Note string `validate:"max:3" name:"Comment"`
+	}
+	assert.Equal(t, "3 field(s) failed: From.City, To.City, Comment", Summary(shipment{Note: "fragile"}))
+}
+
+func TestValidateAndReport(t *testing.T) {
+	type address struct {
+		City string `validate:"min:1"`
+		Zip  string `validate:"len:5"`
+	}
+	type form struct {
+		Name     string `validate:"min:3"`
+		Age      int    `validate:"min:18"`
+		Nickname string `validate:"min:3;warn"`
+		Comment  string
+		Address  address
+	}
+	passed, failed, err := ValidateAndReport(form{Name: "Bob", Age: 17, Nickname: "x", Address: address{Zip: "12345"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name", "Nickname", "Address.Zip"}, passed)
+	assert.Equal(t, []string{"Age", "Address.City"}, failed)
+
+	passed, failed, err = ValidateAndReport(form{Name: "Bob", Age: 18, Address: address{City: "Oslo", Zip: "12345"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name", "Age", "Nickname", "Address.City", "Address.Zip"}, passed)
+	assert.Empty(t, failed)
+
+	_, _, err = ValidateAndReport(42)
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
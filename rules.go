@@ -0,0 +1,436 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// rule is a single validator invocation parsed from a tag, e.g. "min:3", or, if alternatives
+// is not empty, a group of "|"-separated rules of which at least one has to pass.
+type rule struct {
+	name         string
+	arg          string
+	alternatives []rule
+}
+
+// modifiers are the tag tokens without an argument, which change how the other rules of the field apply.
+// "warn" and "info" report the violations of the field with the matching Severity instead of as errors.
+// "dive" applies the rules following it to the elements of the slice, array or map instead, and "keys" and
+// "endkeys" enclose the rules applying to map keys, see diveLevels.
+var modifiers = map[string]struct{}{
+	"warn":    {},
+	"info":    {},
+	"dive":    {},
+	"keys":    {},
+	"endkeys": {},
+}
+
+const (
+	diveModifier    = "dive"
+	keysModifier    = "keys"
+	endkeysModifier = "endkeys"
+)
+
+// diveLevel holds the rules applying at a given depth of a field, see diveLevels.
+type diveLevel struct {
+	rules []rule
+	// keys are the rules applying to the keys of the map the values of this level are taken from.
+	keys []rule
+}
+
+// diveLevels splits rules at their dive modifiers: the rules before the first dive apply to the field,
+// the ones between the first and the second dive to its elements, the ones after the second dive to the
+// elements of its elements, and so on. E.g. "lenmax:3;dive;lenmin:1;dive;between:0,9" allows up to 3 rows of
+// at least one digit each.
+//
+// Right after a dive into a map, the rules enclosed in keys and endkeys apply to the keys of the map instead
+// of its values, e.g. "dive;keys;regexp:^[A-Z];endkeys;max:100". keys anywhere else, a keys without endkeys
+// and an endkeys without keys are syntax errors.
+func diveLevels(rules []rule) ([]diveLevel, error) {
+	levels := []diveLevel{{}}
+	inKeys, afterDive := false, false
+	for _, r := range rules {
+		cur := &levels[len(levels)-1]
+		switch {
+		case r.name == keysModifier:
+			if !afterDive {
+				return nil, ErrInvalidValidatorSyntax
+			}
+			inKeys = true
+		case r.name == endkeysModifier:
+			if !inKeys {
+				return nil, ErrInvalidValidatorSyntax
+			}
+			inKeys = false
+		case inKeys && r.name == diveModifier:
+			return nil, ErrInvalidValidatorSyntax
+		case r.name == diveModifier:
+			levels = append(levels, diveLevel{})
+		case inKeys:
+			cur.keys = append(cur.keys, r)
+		default:
+			cur.rules = append(cur.rules, r)
+		}
+		afterDive = r.name == diveModifier
+	}
+	if inKeys {
+		return nil, ErrInvalidValidatorSyntax
+	}
+	return levels, nil
+}
+
+var severityModifiers = map[string]Severity{
+	"warn": SeverityWarning,
+	"info": SeverityInfo,
+}
+
+// fieldSeverity returns the Severity set by the modifiers among rules; setting several is a syntax error.
+func fieldSeverity(rules []rule) (Severity, error) {
+	severity, found := SeverityError, false
+	for _, r := range rules {
+		if s, ok := severityModifiers[r.name]; ok {
+			if found {
+				return SeverityError, ErrInvalidValidatorSyntax
+			}
+			severity, found = s, true
+		}
+	}
+	return severity, nil
+}
+
+// RulesProvider is implemented by structs that supply the rules of their fields in code, e.g.
+// generated structs whose tags can't be edited. Rules maps field names to rules in the tag syntax;
+// the rules of a field listed there replace the ones of its tag.
+type RulesProvider interface {
+	Rules() map[string]string
+}
+
+var rulesProviderType = reflect.TypeOf((*RulesProvider)(nil)).Elem()
+
+// providedRules returns the rules supplied by v if it is a RulesProvider, or nil.
+func providedRules(v reflect.Value) map[string]string {
+	if !v.CanInterface() {
+		return nil
+	}
+	if p, ok := v.Interface().(RulesProvider); ok {
+		return p.Rules()
+	}
+	return nil
+}
+
+const (
+	whenRule   = "when"
+	skipIfRule = "skipif"
+)
+
+// selectRules drops the rules that don't apply given flags and the sibling fields of parent: the rules
+// following a "when:flag" rule, up to the next when or skipif rule, apply only if flags[flag] is true, e.g.
+// "min:3;when:strict;min:8" always requires 3 characters, and 8 in strict mode. Likewise, the rules
+// following a "skipif:Field value" rule are dropped if the sibling Field, printed with fmt, equals value,
+// e.g. "skipif:Mode simple;min:8". The when and skipif rules themselves are dropped as well.
+func selectRules(rules []rule, flags map[string]bool, parent reflect.Value) ([]rule, error) {
+	res := make([]rule, 0, len(rules))
+	enabled := true
+	for _, r := range rules {
+		switch r.name {
+		case whenRule:
+			enabled = flags[r.arg]
+			continue
+		case skipIfRule:
+			name, value, ok := strings.Cut(r.arg, " ")
+			if !ok {
+				return nil, ErrInvalidValidatorSyntax
+			}
+			sibling := parent.FieldByName(name)
+			if !sibling.IsValid() {
+				return nil, ErrInvalidValidatorSyntax
+			}
+			sibling, isNil := deref(sibling)
+			enabled = isNil || fmt.Sprint(sibling) != value
+			continue
+		}
+		if enabled {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+// checkDuplicateRules returns ErrDuplicateRule if several rules of the same dive level, or several of its
+// key rules, use the same validator. Modifiers may repeat, e.g. "dive;dive;between:0,9", and so may a
+// validator applying at different levels, e.g. "min:1;dive;min:3".
+func checkDuplicateRules(levels []diveLevel) error {
+	for _, level := range levels {
+		if err := checkDuplicates(level.rules); err != nil {
+			return err
+		}
+		if err := checkDuplicates(level.keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkDuplicates(rules []rule) error {
+	seen := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		if len(r.alternatives) != 0 {
+			continue
+		}
+		if _, ok := modifiers[r.name]; ok {
+			continue
+		}
+		if _, ok := seen[r.name]; ok {
+			return errors.Wrap(ErrDuplicateRule, r.name)
+		}
+		seen[r.name] = struct{}{}
+	}
+	return nil
+}
+
+var (
+	rulesetsMu sync.RWMutex
+	rulesets   = make(map[string]string)
+)
+
+// RegisterRuleset stores rules under name, so that tags can reuse them as "ref:name".
+// The rules use the tag syntax, e.g. RegisterRuleset("username", "min:3;max:32").
+func RegisterRuleset(name, rules string) {
+	rulesetsMu.Lock()
+	defer rulesetsMu.Unlock()
+	rulesets[name] = rules
+}
+
+// Rule is a rule of a tag as returned by ParseRules: a validator name and its argument, e.g. "min" and "3"
+// for "min:3", or a modifier such as "dive" with no argument. If Alternatives is not empty, the Rule is a
+// group of "|"-separated rules instead, of which at least one has to pass, and Name and Arg are empty.
+type Rule struct {
+	Name         string
+	Arg          string
+	Alternatives []Rule
+}
+
+// ParseRules parses a tag value the way Validate does, e.g. to analyze tags without running any validation.
+// The "ref:" rules are expanded, while the ${key} references of ValidateWithResolver are kept as is.
+// Like in a tag, an empty or malformed value gives ErrInvalidValidatorSyntax.
+func ParseRules(tag string) ([]Rule, error) {
+	rules, err := parseRules(tag)
+	if err != nil {
+		return nil, err
+	}
+	return exportRules(rules), nil
+}
+
+func exportRules(rules []rule) []Rule {
+	if len(rules) == 0 {
+		return nil
+	}
+	res := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, Rule{Name: r.name, Arg: r.arg, Alternatives: exportRules(r.alternatives)})
+	}
+	return res
+}
+
+// parseRules splits a tag into its ";"-separated rules, expanding the "ref:" ones.
+// Each rule may be a group of "|"-separated alternatives, so "|" binds tighter than ";":
+// "len:3|len:5;in:abc,defgh" means (len:3 or len:5) and in:abc,defgh.
+// An argument or list item quoted as in splitList may contain ";" and "|", e.g. "regexp:'^(a|b)$'";
+// the quotes are kept in the argument of the rule.
+func parseRules(tag string) ([]rule, error) {
+	return parseRulesRef(tag, nil)
+}
+
+// parseRulesRef is parseRules keeping track of the rulesets being expanded, to reject cyclic references.
+func parseRulesRef(tag string, expanding []string) ([]rule, error) {
+	var rules []rule
+	for _, token := range splitRules(tag, ';') {
+		alternatives := splitRules(token, '|')
+		if len(alternatives) == 1 {
+			expanded, err := parseToken(token, expanding)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, expanded...)
+			continue
+		}
+		var group rule
+		for _, alternative := range alternatives {
+			expanded, err := parseToken(alternative, expanding)
+			if err != nil {
+				return nil, err
+			}
+			if len(expanded) != 1 {
+				return nil, ErrInvalidValidatorSyntax
+			}
+			if _, ok := modifiers[expanded[0].name]; ok {
+				return nil, ErrInvalidValidatorSyntax
+			}
+			group.alternatives = append(group.alternatives, expanded[0])
+		}
+		rules = append(rules, group)
+	}
+	return rules, nil
+}
+
+// splitRules splits tag at the sep characters which are not within a quoted argument or list item,
+// i.e. one starting with "'" right after the ":" of a rule or a "," and ending at the next unescaped "'".
+// A quote without a closing one is a plain character.
+func splitRules(tag string, sep byte) []string {
+	var res []string
+	start := 0
+	for i := 0; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case c == sep:
+			res = append(res, tag[start:i])
+			start = i + 1
+		case c == '\'' && opensQuote(tag[start:i]):
+			if end := closingQuote(tag, i); end >= 0 {
+				i = end
+			}
+		}
+	}
+	return append(res, tag[start:])
+}
+
+// opensQuote reports whether a quote following before, the part of a rule up to it, starts a quoted item.
+func opensQuote(before string) bool {
+	before = strings.TrimRight(before, " ")
+	return strings.HasSuffix(before, ":") || strings.HasSuffix(before, ",")
+}
+
+// closingQuote returns the index of the quote closing the one at s[open], skipping the \' and \\ escapes,
+// or -1 if there is none.
+func closingQuote(s string, open int) int {
+	for i := open + 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '\'' || s[i+1] == '\\'):
+			i++
+		case s[i] == '\'':
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteArg returns the content of value if it is a single quoted item, as in splitList, or value itself,
+// so that "regexp:'^(a|b)$'" gets the pattern ^(a|b)$.
+func unquoteArg(value string) string {
+	if !strings.HasPrefix(value, "'") {
+		return value
+	}
+	if items, ok := splitList(value, false); ok && len(items) == 1 {
+		return items[0]
+	}
+	return value
+}
+
+// parseToken parses a single rule or modifier, returning the rules of a ruleset for "ref:".
+func parseToken(token string, expanding []string) ([]rule, error) {
+	name, arg, found := strings.Cut(token, ":")
+	if !found {
+		if _, ok := modifiers[token]; !ok {
+			return nil, ErrInvalidValidatorSyntax
+		}
+		return []rule{{name: token}}, nil
+	}
+	if name != "ref" {
+		return []rule{{name: name, arg: arg}}, nil
+	}
+	for _, ref := range expanding {
+		if ref == arg {
+			return nil, ErrInvalidValidatorSyntax
+		}
+	}
+	rulesetsMu.RLock()
+	ruleset, ok := rulesets[arg]
+	rulesetsMu.RUnlock()
+	if !ok {
+		return nil, ErrInvalidValidatorSyntax
+	}
+	return parseRulesRef(ruleset, append(expanding, arg))
+}
+
+// resolveRules expands the ${key} references in the arguments of rules in place.
+func resolveRules(rules []rule, resolve func(key string) (string, bool)) error {
+	for i := range rules {
+		if err := resolveRules(rules[i].alternatives, resolve); err != nil {
+			return err
+		}
+		arg := rules[i].arg
+		var b strings.Builder
+		for {
+			start := strings.Index(arg, "${")
+			if start < 0 {
+				break
+			}
+			end := strings.IndexByte(arg[start:], '}')
+			if end < 0 {
+				return ErrInvalidValidatorSyntax
+			}
+			resolved, ok := resolve(arg[start+2 : start+end])
+			if !ok {
+				return ErrInvalidValidatorSyntax
+			}
+			b.WriteString(arg[:start])
+			b.WriteString(resolved)
+			arg = arg[start+end+1:]
+		}
+		b.WriteString(arg)
+		rules[i].arg = b.String()
+	}
+	return nil
+}
+
+// splitList splits the comma-separated list argument of a rule, e.g. of "in", into its items. An item may be
+// quoted to hold commas or surrounding spaces, as in "'a,b',c", with \' and \\ escaping a quote and a backslash
+// inside the quotes; the spaces around the quotes are ignored. The unquoted items are trimmed if trim is set.
+// It reports false if a quote is not closed or is followed by anything but a comma.
+func splitList(value string, trim bool) ([]string, bool) {
+	var items []string
+	for {
+		quoted := strings.TrimLeft(value, " ")
+		if !strings.HasPrefix(quoted, "'") {
+			item, rest, found := strings.Cut(value, ",")
+			if trim {
+				item = strings.TrimSpace(item)
+			}
+			items = append(items, item)
+			if !found {
+				return items, true
+			}
+			value = rest
+			continue
+		}
+		var b strings.Builder
+		i, closed := 1, false
+		for ; i < len(quoted); i++ {
+			c := quoted[i]
+			if c == '\\' && i+1 < len(quoted) && (quoted[i+1] == '\'' || quoted[i+1] == '\\') {
+				b.WriteByte(quoted[i+1])
+				i++
+				continue
+			}
+			if c == '\'' {
+				closed = true
+				break
+			}
+			b.WriteByte(c)
+		}
+		if !closed {
+			return nil, false
+		}
+		items = append(items, b.String())
+		rest := strings.TrimLeft(quoted[i+1:], " ")
+		if rest == "" {
+			return items, true
+		}
+		if rest[0] != ',' {
+			return nil, false
+		}
+		value = rest[1:]
+	}
+}
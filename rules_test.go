@@ -0,0 +1,489 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	RegisterRuleset("shortName", "min:2;max:5")
+	RegisterRuleset("greeting", "ref:shortName;in:hi,hello")
+	RegisterRuleset("loopA", "ref:loopB")
+	RegisterRuleset("loopB", "ref:loopA")
+
+	tests := []struct {
+		tag     string
+		want    []rule
+		wantErr error
+	}{
+		{tag: "len:3", want: []rule{{name: "len", arg: "3"}}},
+		{tag: "min:", want: []rule{{name: "min", arg: ""}}},
+		{tag: "url:host=example.com:8080", want: []rule{{name: "url", arg: "host=example.com:8080"}}},
+		{tag: "min:1;max:10", want: []rule{{name: "min", arg: "1"}, {name: "max", arg: "10"}}},
+		{tag: "ref:shortName", want: []rule{{name: "min", arg: "2"}, {name: "max", arg: "5"}}},
+		{tag: "len:3;ref:greeting", want: []rule{
+			{name: "len", arg: "3"}, {name: "min", arg: "2"}, {name: "max", arg: "5"}, {name: "in", arg: "hi,hello"},
+		}},
+		{tag: "warn;min:1", want: []rule{{name: "warn"}, {name: "min", arg: "1"}}},
+		{tag: "uuid:|regexp:^\\d+$", want: []rule{{alternatives: []rule{{name: "uuid"}, {name: "regexp", arg: "^\\d+$"}}}}},
+		{tag: "len:3|len:5;in:abc,defgh", want: []rule{
+			{alternatives: []rule{{name: "len", arg: "3"}, {name: "len", arg: "5"}}}, {name: "in", arg: "abc,defgh"},
+		}},
+		{tag: "regexp:'^(a|b);c$';len:3", want: []rule{{name: "regexp", arg: "'^(a|b);c$'"}, {name: "len", arg: "3"}}},
+		{tag: "in:x,'a|b'|len:1", want: []rule{{alternatives: []rule{{name: "in", arg: "x,'a|b'"}, {name: "len", arg: "1"}}}}},
+		{tag: `regexp:'it\'s|x'`, want: []rule{{name: "regexp", arg: `'it\'s|x'`}}},
+		{tag: "regexp:^it's|len:1", want: []rule{{alternatives: []rule{{name: "regexp", arg: "^it's"}, {name: "len", arg: "1"}}}}},
+		{tag: "regexp:'a|b", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "min:1|ref:shortName", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "min:1|warn", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "min:1|", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "ref:missing", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "ref:loopA", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "len", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "min:1;", wantErr: ErrInvalidValidatorSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := parseRules(tt.tag)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRulesExported(t *testing.T) {
+	RegisterRuleset("exportedName", "min:2;max:5")
+	tests := []struct {
+		tag     string
+		want    []Rule
+		wantErr error
+	}{
+		{tag: "min:1;max:${limit}", want: []Rule{{Name: "min", Arg: "1"}, {Name: "max", Arg: "${limit}"}}},
+		{tag: "dive;ref:exportedName", want: []Rule{{Name: "dive"}, {Name: "min", Arg: "2"}, {Name: "max", Arg: "5"}}},
+		{tag: "uuid:|len:3;in:abc", want: []Rule{
+			{Alternatives: []Rule{{Name: "uuid"}, {Name: "len", Arg: "3"}}}, {Name: "in", Arg: "abc"},
+		}},
+		{tag: "", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "min:1;;max:2", wantErr: ErrInvalidValidatorSyntax},
+		{tag: "min:1|", wantErr: ErrInvalidValidatorSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := ParseRules(tt.tag)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateRuleset(t *testing.T) {
+	RegisterRuleset("nickRules", "min:3;max:8")
+	type user struct {
+		Nick  string `validate:"ref:nickRules"`
+		Alias string `validate:"ref:nickRules;in:neo,trinity,morpheus"`
+		Other string `validate:"ref:unknownRules"`
+	}
+	err := Validate(user{Nick: "bob", Alias: "neo"})
+	assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error())
+
+	type account struct {
+		Nick  string `validate:"ref:nickRules"`
+		Alias string `validate:"ref:nickRules;in:neo,trinity,morpheus"`
+	}
+	assert.NoError(t, Validate(account{Nick: "bob", Alias: "trinity"}))
+	assert.EqualError(t, Validate(account{Nick: "bo", Alias: "morpheus"}), "String length is less than allowed")
+	err = Validate(account{Nick: "bob", Alias: "sm"})
+	assert.EqualError(t, err, "String length is less than allowed"+"Field value isn't allowed")
+	assert.Len(t, err.(ValidationErrors), 2)
+	assert.Len(t, ValidateWithOptions(account{Nick: "bo", Alias: "sm"}, Options{MaxErrors: 2}).(ValidationErrors), 2)
+}
+
+func TestValidateWithResolver(t *testing.T) {
+	env := map[string]string{"MAX_ITEMS": "3", "MIN": "1", "TIERS": "free,pro"}
+	resolve := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	type cart struct {
+		Items []int  `validate:"lenmax:${MAX_ITEMS}"`
+		Count int    `validate:"between:${MIN},${MAX_ITEMS}"`
+		Tier  string `validate:"in:${TIERS},enterprise"`
+	}
+	assert.NoError(t, ValidateWithResolver(cart{Items: []int{1, 2, 3}, Count: 3, Tier: "enterprise"}, resolve))
+	assert.EqualError(t, ValidateWithResolver(cart{Items: []int{1, 2, 3, 4}, Count: 1, Tier: "pro"}, resolve),
+		"Length 4 is more than allowed 3")
+
+	// without a resolver the references are left as is and can't be parsed
+	err := Validate(cart{Items: []int{1}, Count: 1, Tier: "pro"})
+	assert.Len(t, err.(ValidationErrors), 3)
+
+	err = ValidateWithResolver(struct {
+		A int `validate:"max:${UNKNOWN}"`
+		B int `validate:"max:${MAX_ITEMS"`
+		C int `validate:"max:5"`
+	}{C: 6}, resolve)
+	assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error()+ErrInvalidValidatorSyntax.Error()+
+		"Integer is more than allowed")
+}
+
+func TestValidateAlternatives(t *testing.T) {
+	type item struct {
+		ID   string `validate:"uuid:|regexp:^\\d+$"`
+		Code string `validate:"len:3|len:5;in:abc,defgh,xy"`
+	}
+	assert.NoError(t, Validate(item{ID: "123e4567-e89b-12d3-a456-426614174000", Code: "abc"}))
+	assert.NoError(t, Validate(item{ID: "42", Code: "defgh"}))
+
+	err := Validate(item{ID: "abc", Code: "xy"})
+	assert.EqualError(t, err, `String is not a valid UUID or String doesn't match "^\\d+$"`+
+		"lengths don't match or lengths don't match")
+	e := err.(ValidationErrors)
+	assert.Equal(t, "uuid|regexp", e[0].Rule)
+	assert.Equal(t, "len|len", e[1].Rule)
+
+	assert.EqualError(t, Validate(item{ID: "1", Code: "zzz"}), "Field value isn't allowed")
+	assert.EqualError(t, Validate(struct {
+		F string `validate:"regexp:[a-"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+	assert.EqualError(t, Validate(struct {
+		F []string `validate:"uuid:"`
+	}{F: []string{"123E4567-E89B-12D3-A456-426614174000", "123e4567e89b12d3a456426614174000"}}),
+		"The string on position 1 is not allowed: String is not a valid UUID")
+
+	got, err := Explain(item{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ID: uuid() | regexp(^\\d+$)", "Code: len(3) | len(5), in(abc,defgh,xy)"}, got)
+}
+
+type generatedUser struct {
+	Name  string `validate:"min:1"`
+	Email string
+	Age   int
+}
+
+func (generatedUser) Rules() map[string]string {
+	return map[string]string{
+		"Name":  "min:3",
+		"Email": "regexp:@",
+	}
+}
+
+func TestValidateRulesProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       generatedUser
+		wantErr string
+	}{
+		{name: "valid", v: generatedUser{Name: "Bob", Email: "bob@example.com"}},
+		{name: "overridden tag", v: generatedUser{Name: "Bo", Email: "bob@example.com"}, wantErr: "String length is less than allowed"},
+		{name: "untagged field", v: generatedUser{Name: "Bob", Email: "bob"}, wantErr: `String doesn't match "@"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	assert.EqualError(t, ValidateParallel(generatedUser{Name: "Bo"}), `String length is less than allowedString doesn't match "@"`)
+	explained, err := Explain(generatedUser{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name: min(3)", "Email: regexp(@)"}, explained)
+}
+
+func TestValidateSpec(t *testing.T) {
+	type thirdParty struct {
+		Name  string `validate:"min:10"`
+		Email string
+		Age   int
+	}
+	spec := map[string]string{
+		"Email": "regexp:@",
+		"Age":   "between:18,120",
+	}
+	assert.NoError(t, ValidateSpec(thirdParty{Name: "Bob", Email: "bob@example.com", Age: 30}, spec))
+	err := ValidateSpec(thirdParty{Email: "bob", Age: 12}, spec)
+	assert.EqualError(t, err, `String doesn't match "@"`+"Integer is more than allowed")
+	assert.Equal(t, "Age", err.(ValidationErrors)[1].Field)
+
+	assert.EqualError(t, ValidateSpec(thirdParty{}, map[string]string{"Phone": "digits:10"}), "Spec has rules for unknown field Phone")
+	assert.ErrorIs(t, ValidateSpec(42, spec), ErrNotStruct)
+	assert.NoError(t, ValidateSpec(generatedUser{}, nil))
+}
+
+func TestValidateDuplicateRules(t *testing.T) {
+	v := struct {
+		Name  string `validate:"min:3;min:5"`
+		Code  string `validate:"len:3|len:5;in:abc,abcde"`
+		Title string `validate:"min:1;max:3;max:5"`
+	}{Name: "abcd", Code: "abc", Title: "abcd"}
+
+	assert.EqualError(t, Validate(v), "String length is less than allowed"+"String length is more than allowed")
+
+	err := ValidateWithOptions(v, Options{RejectDuplicateRules: true})
+	e := err.(ValidationErrors)
+	assert.Len(t, e, 2)
+	assert.ErrorIs(t, e[0].Err, ErrDuplicateRule)
+	assert.Equal(t, "Name", e[0].Field)
+	assert.EqualError(t, e[1], "max: duplicate validator rule")
+
+	nested := struct {
+		Matrix [][]int          `validate:"dive;dive;between:0,9"`
+		Names  []string         `validate:"min:1;dive;min:3"`
+		Scores map[string]int   `validate:"dive;keys;min:1;endkeys;min:1"`
+		Tags   []string         `validate:"warn;dive;min:1;min:2"`
+		Codes  map[string]int   `validate:"dive;keys;min:1;max:3;min:2;endkeys"`
+		Flags  map[string][]int `validate:"dive;min:1;dive;min:0"`
+	}{Matrix: [][]int{{1}}, Names: []string{"abc"}, Scores: map[string]int{"a": 1}, Tags: []string{"ab"}}
+	err = ValidateWithOptions(nested, Options{RejectDuplicateRules: true})
+	e = err.(ValidationErrors)
+	assert.Len(t, e, 2)
+	assert.Equal(t, "Tags", e[0].Field)
+	assert.EqualError(t, e[0], "min: duplicate validator rule")
+	assert.Equal(t, "Codes", e[1].Field)
+	assert.ErrorIs(t, e[1].Err, ErrDuplicateRule)
+}
+
+func TestValidateDive(t *testing.T) {
+	type board struct {
+		Matrix [][]int        `validate:"lenmax:3;dive;lenmin:1;dive;between:0,9"`
+		Names  []string       `validate:"dive;in:x,o"`
+		Cells  []*int         `validate:"dive;required:;max:1"`
+		Scores map[string]int `validate:"dive;min:0"`
+	}
+	one := 1
+	valid := board{
+		Matrix: [][]int{{1, 2}, {0, 9, 3}},
+		Names:  []string{"x", "o"},
+		Cells:  []*int{&one},
+		Scores: map[string]int{"x": 0},
+	}
+	assert.NoError(t, Validate(valid))
+
+	two := 2
+	err := Validate(board{
+		Matrix: [][]int{{1}, {}, {4, 5, 10}},
+		Names:  []string{"x", "y"},
+		Cells:  []*int{&one, nil, &two},
+		Scores: map[string]int{"b": -1, "a": -2},
+	})
+	assert.EqualError(t, err, "[1]: Length 0 is less than allowed 1"+
+		"[2][2]: Integer is more than allowed"+
+		"[1]: Field value isn't allowed"+
+		"[1]: Field is required"+
+		"[2]: Integer is more than allowed"+
+		"[a]: Integer is less than allowed"+
+		"[b]: Integer is less than allowed")
+	e := err.(ValidationErrors)
+	assert.Equal(t, "Matrix", e[1].Field)
+	assert.Equal(t, "[2][2]", e[1].Path)
+	assert.Equal(t, "between", e[1].Rule)
+
+	violations, err := ValidateWithPositions(board{Matrix: [][]int{{1, -1}}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldViolation{{Field: "Matrix[0][1]", Description: "[0][1]: Integer is more than allowed", Rule: "between"}}, violations)
+
+	assert.EqualError(t, Validate(struct {
+		N []int `validate:"dive;dive;min:1"`
+	}{N: []int{1}}), "[0]: Field of type int: unsupported field type")
+
+	assert.EqualError(t, Validate(struct {
+		Ports map[int]string     `validate:"dive;min:2"`
+		Sizes map[uint8]string   `validate:"dive;min:2"`
+		Ratio map[float64]string `validate:"dive;min:2"`
+	}{
+		Ports: map[int]string{10: "a", 9: "b", -1: "c", 100: "ok"},
+		Sizes: map[uint8]string{200: "a", 30: "b"},
+		Ratio: map[float64]string{1.5: "a", 10: "b"},
+	}), "[-1]: String length is less than allowed"+"[9]: String length is less than allowed"+
+		"[10]: String length is less than allowed"+"[30]: String length is less than allowed"+
+		"[200]: String length is less than allowed"+"[1.5]: String length is less than allowed"+
+		"[10]: String length is less than allowed")
+}
+
+func TestValidateDiveRegexp(t *testing.T) {
+	type resource struct {
+		Labels  map[string]string   `validate:"dive;regexp:^[a-z]+$"`
+		Tags    []string            `validate:"dive;iregexp:^[a-z]+$"`
+		Aliases map[string][]string `validate:"dive;regexp:^[a-z]+$"`
+		Owners  map[string]*string  `validate:"dive;regexp:^[a-z]+$"`
+	}
+	team := "platform"
+	assert.NoError(t, Validate(resource{
+		Labels:  map[string]string{"app": "web", "tier": "frontend"},
+		Tags:    []string{"Prod", "eu"},
+		Aliases: map[string][]string{"web": {"www", "site"}},
+		Owners:  map[string]*string{"team": &team, "oncall": nil},
+	}))
+
+	bad := "Ops!"
+	err := Validate(resource{
+		Labels:  map[string]string{"app": "web", "env": "prod-1", "Tier": "UI"},
+		Tags:    []string{"ok", "not ok"},
+		Aliases: map[string][]string{"web": {"www", "2nd"}},
+		Owners:  map[string]*string{"team": &bad},
+	})
+	assert.EqualError(t, err, `[Tier]: String doesn't match "^[a-z]+$"`+
+		`[env]: String doesn't match "^[a-z]+$"`+
+		`[1]: String doesn't match "^[a-z]+$" case-insensitively`+
+		`[web]: The string on position 1 is not allowed: String doesn't match "^[a-z]+$"`+
+		`[team]: String doesn't match "^[a-z]+$"`)
+	e := err.(ValidationErrors)
+	assert.Equal(t, "Labels", e[0].Field)
+	assert.Equal(t, "[Tier]", e[0].Path)
+	assert.Equal(t, "regexp", e[0].Rule)
+}
+
+func TestValidateDiveKeys(t *testing.T) {
+	type request struct {
+		Headers map[string]string `validate:"dive;keys;regexp:^[A-Z][A-Za-z-]*$;max:16;endkeys;max:10"`
+		Limits  map[string][]int  `validate:"lenmax:2;dive;keys;in:cpu,mem;endkeys;lenmin:1;dive;between:1,64"`
+	}
+	assert.NoError(t, Validate(request{
+		Headers: map[string]string{"Accept": "text/html", "X-Id": "42"},
+		Limits:  map[string][]int{"cpu": {1, 2}, "mem": {64}},
+	}))
+
+	err := Validate(request{
+		Headers: map[string]string{"accept": "text/html", "Content-Type": "application/json"},
+		Limits:  map[string][]int{"cpu": {0}, "gpu": {}},
+	})
+	assert.EqualError(t, err, `[Content-Type]: String length is more than allowed`+
+		`[accept]: String doesn't match "^[A-Z][A-Za-z-]*$"`+
+		"[cpu][0]: Integer is more than allowed"+
+		"[gpu]: Field value isn't allowed"+
+		"[gpu]: Length 0 is less than allowed 1")
+	e := err.(ValidationErrors)
+	assert.Equal(t, "[accept]", e[1].Path)
+	assert.Equal(t, "regexp", e[1].Rule)
+
+	for _, tag := range []string{"keys;min:1;endkeys", "dive;keys;min:1", "dive;min:1;endkeys", "dive;keys;dive;endkeys"} {
+		err := ValidateSpec(request{Headers: map[string]string{"A": "b"}}, map[string]string{"Headers": tag})
+		assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error(), tag)
+	}
+	assert.EqualError(t, Validate(struct {
+		Tags []string `validate:"dive;keys;min:1;endkeys"`
+	}{Tags: []string{"a"}}), "Field of type []string: unsupported field type")
+}
+
+func TestValidateWithFlags(t *testing.T) {
+	type account struct {
+		Password string `validate:"min:3;when:strict;min:8;when:legacy;max:6"`
+		Email    string `validate:"when:strict;regexp:@"`
+	}
+	v := account{Password: "secret7", Email: "bob"}
+	assert.NoError(t, Validate(v))
+	assert.NoError(t, ValidateWithFlags(v, map[string]bool{"unknown": true}))
+	assert.EqualError(t, ValidateWithFlags(v, map[string]bool{"strict": true}),
+		"String length is less than allowed"+`String doesn't match "@"`)
+	assert.EqualError(t, ValidateWithFlags(v, map[string]bool{"legacy": true}), "String length is more than allowed")
+	assert.EqualError(t, ValidateWithFlags(account{Password: "ab", Email: "a@b"}, nil), "String length is less than allowed")
+
+	got, err := Explain(account{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Password: min(3), when(strict), min(8), when(legacy), max(6)", "Email: when(strict), regexp(@)"}, got)
+}
+
+func TestValidateSkipIf(t *testing.T) {
+	type mode string
+	type account struct {
+		Mode     mode
+		Level    *int
+		Password string `validate:"min:3;skipif:Mode simple;min:8;regexp:[0-9]"`
+		PIN      string `validate:"skipif:Level 0;len:4"`
+	}
+	zero, one := 0, 1
+	tests := []struct {
+		name    string
+		v       account
+		wantErr string
+	}{
+		{name: "simple mode skips", v: account{Mode: "simple", Password: "abcd", Level: &zero}},
+		{name: "simple mode keeps earlier rules", v: account{Mode: "simple", Password: "ab", Level: &zero},
+			wantErr: "String length is less than allowed"},
+		{name: "strict mode applies", v: account{Mode: "strict", Password: "abcd", Level: &zero},
+			wantErr: "String length is less than allowed" + `String doesn't match "[0-9]"`},
+		{name: "pointer sibling", v: account{Mode: "simple", Password: "abcd", Level: &one, PIN: "12"},
+			wantErr: "lengths don't match"},
+		{name: "nil sibling", v: account{Mode: "simple", Password: "abcd", PIN: "1234"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	assert.EqualError(t, Validate(struct {
+		Password string `validate:"skipif:Missing simple;min:8"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+	assert.EqualError(t, Validate(struct {
+		Mode     string
+		Password string `validate:"skipif:Mode;min:8"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+
+	got, err := Explain(account{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Password: min(3), skipif(Mode simple), min(8), regexp([0-9])", "PIN: skipif(Level 0), len(4)"}, got)
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value  string
+		trim   bool
+		want   []string
+		wantOk bool
+	}{
+		{value: "a,b", want: []string{"a", "b"}, wantOk: true},
+		{value: "'a,b','c'", want: []string{"a,b", "c"}, wantOk: true},
+		{value: "'a,b', c ,d", trim: true, want: []string{"a,b", "c", "d"}, wantOk: true},
+		{value: " c ,'d'", want: []string{" c ", "d"}, wantOk: true},
+		{value: "' padded ',x", trim: true, want: []string{" padded ", "x"}, wantOk: true},
+		{value: `'it\'s','back\\slash'`, want: []string{"it's", `back\slash`}, wantOk: true},
+		{value: "it's", want: []string{"it's"}, wantOk: true},
+		{value: "'',a", want: []string{"", "a"}, wantOk: true},
+		{value: "'a,b", wantOk: false},
+		{value: "'a'b", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := splitList(tt.value, tt.trim)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateQuotedArguments(t *testing.T) {
+	type listing struct {
+		Format string `validate:"in:'a,b','c', d"`
+		Path   string `validate:"prefixany:'/srv,data/',/tmp/"`
+	}
+	assert.NoError(t, Validate(listing{Format: "a,b", Path: "/srv,data/x"}))
+	assert.NoError(t, Validate(listing{Format: "d", Path: "/tmp/x"}))
+	assert.EqualError(t, Validate(listing{Format: "a", Path: "/srv"}),
+		"Field value isn't allowed"+`String doesn't start with any of "'/srv,data/',/tmp/"`)
+	assert.EqualError(t, Validate(struct {
+		Format string `validate:"in:'a,b"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// timeLayouts are the layouts accepted for time.Time bounds, each bound may use either of them.
+// Bounds without a time zone are in UTC.
+var timeLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// parseDurations parses the bounds with time.ParseDuration, reporting false if any of them is malformed.
+func parseDurations(bounds ...string) ([]time.Duration, bool) {
+	res := make([]time.Duration, 0, len(bounds))
+	for _, bound := range bounds {
+		d, err := time.ParseDuration(bound)
+		if err != nil {
+			return nil, false
+		}
+		res = append(res, d)
+	}
+	return res, true
+}
+
+// validateDuration checks a time.Duration value against the optional min and max bounds.
+func validateDuration(v reflect.Value, min, max *time.Duration) (bool, error) {
+	d := time.Duration(v.Int())
+	if min != nil && d < *min {
+		return false, ValidationError{Err: errors.Errorf("Duration %s is less than allowed %s", d, *min)}
+	}
+	if max != nil && d > *max {
+		return false, ValidationError{Err: errors.Errorf("Duration %s is more than allowed %s", d, *max)}
+	}
+	return true, nil
+}
+
+// parseTimes parses the bounds with one of timeLayouts, reporting false if any of them is malformed.
+func parseTimes(bounds ...string) ([]time.Time, bool) {
+	res := make([]time.Time, 0, len(bounds))
+	for _, bound := range bounds {
+		var t time.Time
+		var err error
+		for _, layout := range timeLayouts {
+			if t, err = time.Parse(layout, bound); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, false
+		}
+		res = append(res, t.UTC())
+	}
+	return res, true
+}
+
+// parseTimeBetween parses the "from,to" argument of between for time.Time fields, with an
+// optional third "exclusive" item making the bounds themselves fail, e.g. "between:2020-01-01,2030-01-01,exclusive".
+func parseTimeBetween(limits []string) ([]time.Time, bool, bool) {
+	exclusive := false
+	if len(limits) == 3 && limits[2] == "exclusive" {
+		limits, exclusive = limits[:2], true
+	}
+	bounds, ok := parseTimes(limits...)
+	if !ok || len(bounds) != 2 {
+		return nil, false, false
+	}
+	return bounds, exclusive, true
+}
+
+// validateTime checks a time.Time value against the optional min and max bounds, comparing instants
+// regardless of the time zones. The bounds are inclusive unless exclusive is set.
+func validateTime(v reflect.Value, min, max *time.Time, exclusive bool) (bool, error) {
+	if !v.CanInterface() {
+		return false, ValidationError{Err: ErrValidateForUnexportedFields}
+	}
+	t := v.Interface().(time.Time).UTC()
+	if min != nil && (t.Before(*min) || exclusive && t.Equal(*min)) {
+		return false, ValidationError{Err: errors.Errorf("Time %s is before allowed %s", t.Format(time.RFC3339), min.Format(time.RFC3339))}
+	}
+	if max != nil && (t.After(*max) || exclusive && t.Equal(*max)) {
+		return false, ValidationError{Err: errors.Errorf("Time %s is after allowed %s", t.Format(time.RFC3339), max.Format(time.RFC3339))}
+	}
+	return true, nil
+}
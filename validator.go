@@ -1,18 +1,79 @@
 package validation
 
 import (
+	"context"
+	"encoding"
+	"fmt"
 	"github.com/pkg/errors"
+	"math"
+	"net/mail"
+	"net/url"
 	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unsafe"
 )
 
 var ErrNotStruct = errors.New("wrong argument given, should be a struct")
 var ErrInvalidValidatorSyntax = errors.New("invalid validator syntax")
 var ErrValidateForUnexportedFields = errors.New("validation for unexported field is not allowed")
+var ErrDuplicateRule = errors.New("duplicate validator rule")
+var ErrMaxDepth = errors.New("nested structs are deeper than allowed")
+
+// ErrUnsupportedFieldType is the error of a validator given a field of a type it can't check, e.g. "len:3"
+// on an int, while ErrInvalidValidatorSyntax is the one of malformed rules.
+var ErrUnsupportedFieldType = errors.New("unsupported field type")
+
+// ruleError reports whether err is a ValidationError about the rule rather than the value, see isRuleError.
+func ruleError(err error) bool {
+	validationErr, ok := err.(ValidationError)
+	return ok && isRuleError(validationErr.Err)
+}
+
+// isRuleError reports whether err is about the rule rather than the value of the field: ErrInvalidValidatorSyntax
+// or ErrUnsupportedFieldType.
+func isRuleError(err error) bool {
+	return errors.Is(err, ErrInvalidValidatorSyntax) || errors.Is(err, ErrUnsupportedFieldType)
+}
+
+// unsupportedType is the violation of a validator given the value v of a type it can't check.
+func unsupportedType(v reflect.Value) ValidationError {
+	return ValidationError{Err: errors.Wrapf(ErrUnsupportedFieldType, "Field of type %s", v.Type())}
+}
 
 type ValidationError struct {
 	Err error
+	// Field is the name of the struct field the violation is about
+	Field string
+	// alias is the friendly name of the field from its `name` tag, if any
+	alias string
+	// Rule is the name of the failed validator, e.g. "min"; it is empty if the tag itself is malformed
+	Rule string
+	// Path is the position of the offending element in the field for the rules following a dive, e.g. "[1][2]"
+	Path string
+	// elem is the offending slice element at position index, for violations about a single element
+	elem  reflect.Value
+	index int
+	// Severity is set by the warn and info modifiers of the field; it defaults to SeverityError
+	Severity Severity
+}
+
+func elemError(v reflect.Value, i int, err error) ValidationError {
+	return ValidationError{Err: err, elem: v.Index(i), index: i}
+}
+
+// displayName returns the name of the field to show to end users: its alias if it has one, following
+// the path of the nested struct holding the field, e.g. "Address.City name".
+func (ve ValidationError) displayName() string {
+	if ve.alias != "" {
+		return ve.Field[:strings.LastIndexByte(ve.Field, '.')+1] + ve.alias
+	}
+	return ve.Field
 }
 
 func (ve ValidationError) Error() string {
@@ -29,289 +90,2493 @@ func (vs ValidationErrors) Error() string {
 	return res
 }
 
+// ValidatorFunc checks a field value against the argument of its rule. It reports a violation
+// by returning false and a ValidationError; any other error aborts the whole validation.
+type ValidatorFunc func(v reflect.Value, arg string) (bool, error)
+
+var validators = map[string]ValidatorFunc{
+	"len":           validateLen,
+	"in":            validateIn,
+	"min":           validateMin,
+	"max":           validateMax,
+	"between":       validateBetween,
+	"url":           validateURL,
+	"notblank":      validateNotBlank,
+	"digits":        validateDigits,
+	"digitsbetween": validateDigitsBetween,
+	"isbn":          validateISBN,
+	"country":       validateCountry,
+	"currency":      validateCurrency,
+	"lenmin":        validateLenMin,
+	"lenmax":        validateLenMax,
+	"eqapprox":      validateEqApprox,
+	"neapprox":      validateNeApprox,
+	"uuid":          validateUUID,
+	"regexp":        validateRegexp,
+	"numlen":        validateNumLen,
+	"onlyrunes":     validateOnlyRunes,
+	"required":      validateRequired,
+	"hexcolor":      validateHexColor,
+	"password":      validatePassword,
+	"isdefault":     validateIsDefault,
+	"capmin":        validateCapMin,
+	"capmax":        validateCapMax,
+	"emaildomain":   validateEmailDomain,
+	"bitmask":       validateBitmask,
+	"iregexp":       validateIRegexp,
+	"graphlen":      validateGraphLen,
+	"graphmin":      validateGraphMin,
+	"graphmax":      validateGraphMax,
+	"prefixany":     validatePrefixAny,
+	"suffixany":     validateSuffixAny,
+	"uniquefold":    validateUniqueFold,
+	"enum":          validateEnum,
+	"nonnil":        validateNonNil,
+	"runerange":     validateRuneRange,
+	"count":         validateCount,
+}
+
+// textValidators are the string-oriented validators, which check encoding.TextMarshaler fields by their
+// text form, and non-string fmt.Stringer fields, or slices of them, by their String form if they can't check
+// the fields as they are, e.g. "in:Active" on a status int. They check []byte fields as strings,
+// except in, which only does if its tokens aren't integers.
+var textValidators = map[string]struct{}{
+	"len":         {},
+	"in":          {},
+	"url":         {},
+	"notblank":    {},
+	"isbn":        {},
+	"country":     {},
+	"currency":    {},
+	"uuid":        {},
+	"regexp":      {},
+	"onlyrunes":   {},
+	"hexcolor":    {},
+	"emaildomain": {},
+	"iregexp":     {},
+	"graphlen":    {},
+	"graphmin":    {},
+	"graphmax":    {},
+	"prefixany":   {},
+	"suffixany":   {},
+	"runerange":   {},
+	"count":       {},
+}
+
+// byteStringValidators are the validators which, besides textValidators, check []byte fields as strings,
+// e.g. "max:1024" bounds the number of bytes instead of the value of each of them.
+var byteStringValidators = map[string]struct{}{
+	"min":     {},
+	"max":     {},
+	"between": {},
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// FieldContext describes the field checked by a FieldValidatorFunc.
+type FieldContext struct {
+	// Value is the value of the field.
+	Value reflect.Value
+	// Name is the name of the field.
+	Name string
+	// Tag is the full tag of the field.
+	Tag reflect.StructTag
+	// Parent is the struct holding the field.
+	Parent reflect.Value
+	// Arg is the argument of the rule being checked, e.g. "3" for "min:3".
+	Arg string
+}
+
+// FieldValidatorFunc is a ValidatorFunc that can inspect the whole struct holding the field.
+type FieldValidatorFunc func(fc FieldContext) (bool, error)
+
+var fieldValidators = map[string]FieldValidatorFunc{
+	"maxlenfield": validateMaxLenField,
+	"minlenfield": validateMinLenField,
+	"method":      validateMethod,
+}
+
+// Validate checks the exported fields of the struct v against their `validate` tags,
+// or against the rules supplied by v if it is a RulesProvider. v may also be a reflect.Value
+// holding the struct; if it is reached through an unexported field, the fields whose values can only
+// be read through Interface, e.g. time.Time ones, are reported as ErrValidateForUnexportedFields.
+// Violations are reported in field declaration order, so the resulting ValidationErrors
+// are stable between calls for the same input.
 func Validate(v any) error {
-	var vs ValidationErrors
-	vType := reflect.TypeOf(v)
-	vValue := reflect.ValueOf(v)
-	validators := make(map[string]func(reflect.Value, string) (bool, error))
-	if vType.Kind() != reflect.Struct {
-		return ErrNotStruct
+	return ValidateWithOptions(v, Options{})
+}
+
+// ValidateTyped is like Validate, but generic over the type of v, so the call site keeps
+// the static type of the struct and tooling can infer it.
+func ValidateTyped[T any](v T) error {
+	return Validate(v)
+}
+
+// Options tunes the behaviour of ValidateWithOptions. The zero value matches Validate.
+type Options struct {
+	// MaxErrors stops the validation once that many violations are collected; 0 means unlimited.
+	MaxErrors int
+	// TagKey is the struct tag holding the rules; empty means "validate".
+	TagKey string
+	// IncludeValue appends the offending value (or slice index and element) to violation messages.
+	// It is off by default, as the values may be sensitive.
+	IncludeValue bool
+	// EmptyInIsSyntaxError makes an empty "in:" set fail with ErrInvalidValidatorSyntax
+	// instead of rejecting every value.
+	EmptyInIsSyntaxError bool
+	// RespectJSONOmitempty skips the fields whose `json` tag has the omitempty option while they are zero.
+	RespectJSONOmitempty bool
+	// AllowReversedRange makes the built-in between rule accept bounds in descending order, e.g.
+	// "between:100,0" meaning "between:0,100", instead of failing with ErrInvalidValidatorSyntax.
+	AllowReversedRange bool
+	// RejectDuplicateRules makes a field using the same validator twice, e.g. "min:3;min:5", fail with ErrDuplicateRule.
+	RejectDuplicateRules bool
+	// MaxDepth is the number of levels of nested struct fields validated before failing with ErrMaxDepth;
+	// 0 means 32. The deeper structs are only a failure if they, or the structs nested in them, have rules.
+	MaxDepth int
+	// OnValidatorRun, if set, is called with the name and the duration of each validator run, e.g. to
+	// find the slow custom validators.
+	OnValidatorRun func(name string, d time.Duration)
+
+	// Normalizers, keyed by field name, transform a copy of a string field, or of the
+	// elements of a string slice field, before it is validated, e.g. strings.ToLower.
+	Normalizers map[string]func(string) string
+
+	// Translator, if set, gives the message of a violation of the rule by the field, e.g. from a message
+	// catalog for the user's locale. The English message is kept if it returns an empty string.
+	Translator func(rule, field string) string
+
+	// unexported enables reading unexported fields, see ValidateUnsafe.
+	unexported bool
+	// ctx is the context of ValidateContext, or nil.
+	ctx context.Context
+	// resolve expands the ${key} references in rule arguments, see ValidateWithResolver.
+	resolve func(key string) (string, bool)
+	// rules are the rules supplied by a RulesProvider or ValidateSpec, keyed by field name.
+	rules map[string]string
+	// ignoreTags makes rules the only source of rules, see ValidateSpec.
+	ignoreTags bool
+	// flags enables the rules following the when rules, see ValidateWithFlags.
+	flags map[string]bool
+	// defaults are the rules of the untagged fields by kind, see ValidateWithDefaults.
+	defaults map[reflect.Kind]string
+	// root is the validated struct if it is a copy, whose original is not validated again, see seedRoot.
+	root reflect.Value
+}
+
+const defaultTagKey = "validate"
+
+const defaultMaxDepth = 32
+
+// nameTagKey is the tag giving a field the friendly name used in FieldViolation, e.g. `name:"First name"`.
+const nameTagKey = "name"
+
+func (opts Options) tagKey() string {
+	if opts.TagKey == "" {
+		return defaultTagKey
+	}
+	return opts.TagKey
+}
+
+func (opts Options) maxDepth() int {
+	if opts.MaxDepth == 0 {
+		return defaultMaxDepth
 	}
+	return opts.MaxDepth
+}
 
-	validators["len"] = validateLen
-	validators["in"] = validateIn
-	validators["min"] = validateMin
-	validators["max"] = validateMax
-	validators["between"] = validateBetween
+// ValidateWithTagKey is like Validate, but reads the rules from the key tag instead of `validate`.
+func ValidateWithTagKey(v any, key string) error {
+	return ValidateWithOptions(v, Options{TagKey: key})
+}
 
-	for i := 0; i < vType.NumField(); i++ {
-		curField := vType.Field(i)
-		tagValue, ok := curField.Tag.Lookup("validate")
-		if !ok {
-			continue
-		} else if !curField.IsExported() {
-			vs = append(vs, ValidationError{ErrValidateForUnexportedFields})
-			continue
+// ValidateUnsafe is like Validate, but also validates the tagged unexported fields
+// instead of reporting ErrValidateForUnexportedFields. The values of such fields are
+// read through package unsafe, bypassing the visibility rules of the language, so it
+// is meant for tests and internal tooling only.
+func ValidateUnsafe(v any) error {
+	return ValidateWithOptions(v, Options{unexported: true})
+}
+
+// ValidateWithResolver is like Validate, but replaces each ${key} in the rule arguments with
+// resolve(key), e.g. to read "max:${MAX_ITEMS}" from the environment with os.LookupEnv.
+// A reference resolve does not know makes the field fail with ErrInvalidValidatorSyntax.
+func ValidateWithResolver(v any, resolve func(key string) (string, bool)) error {
+	return ValidateWithOptions(v, Options{resolve: resolve})
+}
+
+// ValidateSpec is like Validate, but ignores the tags of v and takes the rules from spec instead,
+// which maps field names to rules in the tag syntax. A field of spec that v doesn't have is an error.
+func ValidateSpec(v any, spec map[string]string) error {
+	vValue, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	vType := vValue.Type()
+	for name := range spec {
+		if _, ok := vType.FieldByName(name); !ok {
+			return errors.Errorf("Spec has rules for unknown field %s", name)
 		}
-		rule := strings.Split(tagValue, ":")
-		if len(rule) != 2 {
-			vs = append(vs, ValidationError{ErrInvalidValidatorSyntax})
-			continue
+	}
+	return ValidateWithOptions(v, Options{rules: spec, ignoreTags: true})
+}
+
+// ValidateField applies rules in the tag syntax to a standalone value, e.g. ValidateField(name, "min:3;max:32"),
+// as if it were a field with these rules. The rules comparing fields have no sibling to compare with.
+// It returns ErrInvalidValidatorSyntax if the rules are malformed, and ValidationErrors if value breaks them.
+func ValidateField(value any, rules string) error {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		// a nil value is checked as a nil interface, which only required and isdefault can tell apart
+		v = reflect.ValueOf(&value).Elem()
+	}
+	parent := reflect.ValueOf(struct{}{})
+	parsed, err := parseRules(rules)
+	if err == nil {
+		parsed, err = selectRules(parsed, nil, parent)
+	}
+	if err != nil {
+		return err
+	}
+	severity, err := fieldSeverity(parsed)
+	if err != nil {
+		return err
+	}
+	levels, err := diveLevels(parsed)
+	if err != nil {
+		return err
+	}
+	elem, isNil := deref(v)
+	vs, err := checkValue(FieldContext{Value: elem, Parent: parent}, v, isNil, levels, "", Options{})
+	if err != nil {
+		return err
+	}
+	var res Result
+	for i := range vs {
+		if errors.Is(vs[i].Err, ErrInvalidValidatorSyntax) {
+			return ErrInvalidValidatorSyntax
 		}
-		validator, ok := validators[rule[0]]
-		if !ok {
-			vs = append(vs, ValidationError{errors.New("Unexpected validator option")})
+		if errors.Is(vs[i].Err, ErrUnsupportedFieldType) {
+			return vs[i].Err
+		}
+		vs[i].Severity = severity
+	}
+	res.add(vs, 0)
+	return res.err()
+}
+
+// ValidateAtLeastOne checks that at least one of the named fields of the struct v is set, i.e. is not
+// the zero value of its type, e.g. ValidateAtLeastOne(contact, "Email", "Phone"). The tags of v are not used.
+// It returns ValidationErrors if none is set, and a plain error if there is no field to check or v has no
+// field with one of the names.
+func ValidateAtLeastOne(v any, fields ...string) error {
+	set, err := countSet(v, fields)
+	if err != nil {
+		return err
+	}
+	if set == 0 {
+		return ValidationErrors{{Err: errors.Errorf("At least one of the fields %s must be set", strings.Join(fields, ", "))}}
+	}
+	return nil
+}
+
+// ValidateExactlyOne is like ValidateAtLeastOne, but also fails if more than one of the fields is set,
+// e.g. for oneof-style payloads.
+func ValidateExactlyOne(v any, fields ...string) error {
+	set, err := countSet(v, fields)
+	if err != nil {
+		return err
+	}
+	switch {
+	case set == 0:
+		return ValidationErrors{{Err: errors.Errorf("Exactly one of the fields %s must be set, none is", strings.Join(fields, ", "))}}
+	case set > 1:
+		return ValidationErrors{{Err: errors.Errorf("Exactly one of the fields %s must be set, %d are", strings.Join(fields, ", "), set)}}
+	}
+	return nil
+}
+
+// countSet returns how many of the named fields of the struct v are not zero.
+func countSet(v any, fields []string) (int, error) {
+	vValue, err := structValue(v)
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) == 0 {
+		return 0, errors.New("No fields given to check")
+	}
+	set := 0
+	for _, name := range fields {
+		field := vValue.FieldByName(name)
+		if !field.IsValid() {
+			return 0, errors.Errorf("Struct has no field %s", name)
+		}
+		if !isZero(field) {
+			set++
+		}
+	}
+	return set, nil
+}
+
+// ValidateFieldOrder checks that the exported fields of the struct v are exactly the expected ones,
+// in that order, e.g. for a struct mirroring a wire layout. The error names the first mismatch.
+func ValidateFieldOrder(v any, expected []string) error {
+	vValue, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	vType := vValue.Type()
+	pos := 0
+	for i := 0; i < vType.NumField(); i++ {
+		field := vType.Field(i)
+		if !field.IsExported() {
 			continue
 		}
-		if ok, err := validator(vValue.Field(i), rule[1]); !ok {
-			if validationErr, isValidationErr := err.(ValidationError); !isValidationErr {
-				return err
-			} else {
-				vs = append(vs, validationErr)
-				// изначально было вот так:
-				// vs = append(vs, ValidationError{fmt.Errorf("\"%s\" field validation failed: %w", curField.Name, validationErr)})
-				// но некоторые тесты требуют жёсткого совпадения текста ошибок: оборачивать их не получается
-			}
+		if pos >= len(expected) {
+			return errors.Errorf("Unexpected field %s at position %d", field.Name, pos)
+		} else if field.Name != expected[pos] {
+			return errors.Errorf("Field at position %d is %s, expected %s", pos, field.Name, expected[pos])
 		}
+		pos++
 	}
-	if len(vs) == 0 {
-		return nil
-	} else {
-		return vs
+	if pos < len(expected) {
+		return errors.Errorf("Missing field %s at position %d", expected[pos], pos)
 	}
+	return nil
 }
 
-func validateLen(v reflect.Value, value string) (bool, error) {
-	expected, err := strconv.Atoi(value)
+// ValidateWithFlags is like Validate, but applies the rules following a "when:flag" rule only if
+// flags[flag] is true, e.g. "when:strict;min:8" for a stricter validation in some environments.
+// Validate never applies such rules.
+func ValidateWithFlags(v any, flags map[string]bool) error {
+	return ValidateWithOptions(v, Options{flags: flags})
+}
+
+// ValidateWithDefaults is like Validate, but applies defaults[kind] to the exported fields without a tag of
+// that kind, or pointing to it, e.g. {reflect.String: "notblank:"} for all the untagged string fields.
+// A tag on a field replaces its default rules.
+func ValidateWithDefaults(v any, defaults map[reflect.Kind]string) error {
+	return ValidateWithOptions(v, Options{defaults: defaults})
+}
+
+// ValidateWithOptions is like Validate, but configured by opts.
+func ValidateWithOptions(v any, opts Options) error {
+	res, err := check(v, opts)
 	if err != nil {
-		return false, ValidationError{ErrInvalidValidatorSyntax}
+		return err
 	}
-	switch v.Interface().(type) {
-	case string:
-		if len(v.String()) != expected {
-			return false, ValidationError{errors.New("lengths don't match")}
+	return res.err()
+}
+
+// structValue returns the struct to validate given as v, which may also be a reflect.Value holding it,
+// e.g. one created with reflect.New for a type only known at run time.
+func structValue(v any) (reflect.Value, error) {
+	vValue, ok := v.(reflect.Value)
+	if !ok {
+		vValue = reflect.ValueOf(v)
+	}
+	if !vValue.IsValid() || vValue.Kind() != reflect.Struct {
+		return reflect.Value{}, ErrNotStruct
+	}
+	return vValue, nil
+}
+
+// check collects the violations found in v, including the ones in its nested struct fields, see checkNested.
+func check(v any, opts Options) (Result, error) {
+	var res Result
+	vValue, err := structValue(v)
+	if err != nil {
+		return res, err
+	}
+	vType := vValue.Type()
+	visiting := seedRoot(vValue, &opts)
+	if opts.unexported && !vValue.CanAddr() {
+		if !vValue.CanInterface() {
+			// a copy of a struct reached through an unexported field can't be made without its address
+			return res, ErrValidateForUnexportedFields
 		}
-		return true, nil
-	case []string:
-		var slice []string
-		var ok bool
-		if slice, ok = v.Interface().([]string); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+		// unsafe access needs field addresses, so work on an addressable copy
+		addressable := reflect.New(vType).Elem()
+		addressable.Set(vValue)
+		vValue = addressable
+	}
+	_, err = checkStruct(&res, vValue, opts, "", 0, visiting)
+	return res, err
+}
+
+// visit is a struct reached through a pointer, which is not validated again while validating it.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// seedRoot returns the structs being visited before validating vValue: vValue itself if it is addressable,
+// so that a pointer cycle back to it doesn't validate it again. Otherwise vValue is a copy whose original
+// can only be recognized by its field values, so it is kept as opts.root for checkNested.
+func seedRoot(vValue reflect.Value, opts *Options) map[visit]struct{} {
+	visiting := make(map[visit]struct{})
+	if vValue.CanAddr() {
+		visiting[visit{ptr: vValue.Addr().Pointer(), typ: reflect.PointerTo(vValue.Type())}] = struct{}{}
+	} else {
+		opts.root = vValue
+	}
+	return visiting
+}
+
+// shallowEqual reports whether a and b, of the same type, hold the same values, comparing pointers, maps,
+// slices, chans and funcs by address rather than by what they point to. It reads a and b through their Kind
+// accessors only, so that values reached through unexported fields can be compared as well.
+func shallowEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	case reflect.Slice:
+		return a.Pointer() == b.Pointer() && a.Len() == b.Len()
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
 		}
-		for i, elem := range slice {
-			if len(elem) != expected {
-				return false, ValidationError{errors.Errorf("The string on position %d is shorter than allowed", i)}
+		return a.Elem().Type() == b.Elem().Type() && shallowEqual(a.Elem(), b.Elem())
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if !shallowEqual(a.Index(i), b.Index(i)) {
+				return false
 			}
 		}
-		return true, nil
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !shallowEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
 	default:
-		return false, ValidationError{ErrInvalidValidatorSyntax}
+		return false
 	}
 }
 
-func validateIn(v reflect.Value, value string) (bool, error) {
-	if len(value) == 0 {
-		return false, ValidationError{errors.New("Field value isn't allowed")}
-	}
-	tokens := strings.Split(value, ",")
-	tokensSet := make(map[string]struct{})
-	for _, elem := range tokens {
-		tokensSet[elem] = struct{}{}
+// hasRules reports whether the fields of the struct type t, or of the structs nested in it, have rules,
+// so that validating a value of t can find violations; seen are the types already being checked.
+func hasRules(t reflect.Type, opts Options, seen map[reflect.Type]struct{}) bool {
+	if t.Implements(rulesProviderType) {
+		return true
 	}
-	switch v.Interface().(type) {
-	case string:
-		if _, ok := tokensSet[v.String()]; ok {
-			return true, nil
+	seen[t] = struct{}{}
+	opts.rules = nil
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := fieldRules(f, opts); ok {
+			return true
 		}
-		return false, ValidationError{errors.New("Field value isn't allowed")}
-	case int:
-		for key := range tokensSet {
-			val, err := strconv.Atoi(key)
-			if err != nil {
-				return false, ValidationError{ErrInvalidValidatorSyntax}
-			}
-			if int64(val) == v.Int() {
-				return true, nil
-			}
+		nested := f.Type
+		if nested.Kind() == reflect.Pointer {
+			nested = nested.Elem()
 		}
-		return false, ValidationError{errors.New("Field value isn't allowed")}
-	case []string:
-		var slice []string
-		var ok bool
-		if slice, ok = v.Interface().([]string); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+		if _, ok := seen[nested]; ok || !f.IsExported() || nested.Kind() != reflect.Struct || isSQLNull(nested) {
+			continue
 		}
-		for i, elem := range slice {
-			if _, ok := tokensSet[elem]; !ok {
-				return false, ValidationError{errors.Errorf("The string on position %d is not allowed", i)}
-			}
+		if hasRules(nested, opts, seen) {
+			return true
 		}
-		return true, nil
-	case []int:
-		tokensSetInt := make(map[int]struct{})
-		for elem := range tokensSet {
-			elemInt, err := strconv.Atoi(elem)
-			if err != nil {
-				return false, ValidationError{ErrInvalidValidatorSyntax}
-			}
-			tokensSetInt[elemInt] = struct{}{}
+	}
+	return false
+}
 
+// checkStruct adds the violations found in the fields of vValue and of its nested structs to res.
+// prefix is the path of vValue in the validated struct, e.g. "Order.Address.", at the given depth,
+// and visiting are the structs reached through pointers on that path. It reports whether
+// opts.MaxErrors is reached.
+func checkStruct(res *Result, vValue reflect.Value, opts Options, prefix string, depth int, visiting map[visit]struct{}) (bool, error) {
+	if !opts.ignoreTags {
+		opts.rules = providedRules(vValue)
+	}
+	for i := 0; i < vValue.NumField(); i++ {
+		if opts.ctx != nil {
+			if err := opts.ctx.Err(); err != nil {
+				return false, err
+			}
 		}
-		var slice []int
-		var ok bool
-		if slice, ok = v.Interface().([]int); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+		fieldErrs, err := validateField(vValue, i, opts)
+		if err != nil {
+			return false, err
 		}
-		for i, elem := range slice {
-			if _, ok := tokensSetInt[elem]; !ok {
-				return false, ValidationError{errors.Errorf("The integer on position %d is less than allowed", i)}
-			}
+		for j := range fieldErrs {
+			fieldErrs[j].Field = prefix + fieldErrs[j].Field
+		}
+		curField := vValue.Type().Field(i)
+		if _, ok := fieldRules(curField, opts); ok {
+			res.checked = append(res.checked, prefix+curField.Name)
+		}
+		if res.add(fieldErrs, opts.MaxErrors) {
+			return true, nil
+		}
+		if full, err := checkNested(res, vValue, i, opts, prefix, depth, visiting); full || err != nil {
+			return full, err
 		}
-		return true, nil
-	default:
-		return false, ValidationError{ErrInvalidValidatorSyntax}
 	}
+	return false, nil
 }
 
-func validateMin(v reflect.Value, value string) (bool, error) {
-	min, err := strconv.Atoi(value)
-	if err != nil {
-		return false, ValidationError{ErrInvalidValidatorSyntax}
+// checkNested validates the fields of the i-th field of parent if it is an exported struct, or a non-nil
+// pointer to one, naming them after it, e.g. "Address.City". The fields of a struct on more than
+// opts.MaxDepth levels make it fail with ErrMaxDepth, unless none of them has rules, while a struct
+// reached again through a pointer cycle, including the validated one, is skipped. The validators of ValidateSpec and the Normalizers only apply to the outer struct.
+func checkNested(res *Result, parent reflect.Value, i int, opts Options, prefix string, depth int, visiting map[visit]struct{}) (bool, error) {
+	curField := parent.Type().Field(i)
+	if opts.ignoreTags || !curField.IsExported() {
+		return false, nil
 	}
-	switch v.Interface().(type) {
-	case string:
-		if len(v.String()) >= min {
-			return true, nil
-		} else {
-			return false, ValidationError{errors.New("String length is less than allowed")}
+	value := parent.Field(i)
+	var key visit
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return false, nil
 		}
-	case int:
-		if v.Int() >= int64(min) {
-			return true, nil
-		} else {
-			return false, ValidationError{errors.New("Integer is less than allowed")}
+		key = visit{ptr: value.Pointer(), typ: value.Type()}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct || isSQLNull(value.Type()) {
+		return false, nil
+	}
+	path := prefix + curField.Name
+	if depth >= opts.maxDepth() {
+		if !hasRules(value.Type(), opts, make(map[reflect.Type]struct{})) {
+			return false, nil
+		}
+		return false, errors.Wrap(ErrMaxDepth, path)
+	}
+	if key.typ != nil {
+		if _, ok := visiting[key]; ok {
+			return false, nil
 		}
-	case []int:
-		var slice []int
-		var ok bool
-		if slice, ok = v.Interface().([]int); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+		if opts.root.IsValid() && value.Type() == opts.root.Type() && shallowEqual(value, opts.root) {
+			// the original of the validated copy
+			return false, nil
 		}
-		for i, elem := range slice {
-			if elem < min {
-				return false, ValidationError{errors.Errorf("The integer on position %d is less than allowed", i)}
+		visiting[key] = struct{}{}
+		defer delete(visiting, key)
+	}
+	opts.Normalizers = nil
+	return checkStruct(res, value, opts, path+".", depth+1, visiting)
+}
+
+// ValidateParallel is like Validate, but runs the validators of different fields
+// concurrently on a pool of at most GOMAXPROCS goroutines. The order of the
+// resulting ValidationErrors is the same as for Validate.
+func ValidateParallel(v any) error {
+	vValue, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	vType := vValue.Type()
+
+	type result struct {
+		validationErrs ValidationErrors
+		err            error
+	}
+	opts := Options{rules: providedRules(vValue)}
+	visiting := seedRoot(vValue, &opts)
+	results := make([]result, vType.NumField())
+	jobs := make(chan int)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(results) {
+		workers = len(results)
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// each worker writes only to its own slots, so no locking is needed here
+				results[i].validationErrs, results[i].err = validateField(vValue, i, opts)
 			}
+		}()
+	}
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var res Result
+	for i, fieldRes := range results {
+		if fieldRes.err != nil {
+			return fieldRes.err
 		}
-		return true, nil
-	case []string:
-		var slice []string
-		var ok bool
-		if slice, ok = v.Interface().([]string); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+		res.add(fieldRes.validationErrs, 0)
+		if _, err := checkNested(&res, vValue, i, opts, "", 0, visiting); err != nil {
+			return err
 		}
-		for i, elem := range slice {
-			if len(elem) < min {
-				return false, ValidationError{errors.Errorf("The string on position %d is shorter than allowed", i)}
+	}
+	return res.err()
+}
+
+// fieldRules returns the rules of the field in the tag syntax, and whether it has any, from its tag,
+// opts.rules or opts.defaults.
+func fieldRules(curField reflect.StructField, opts Options) (string, bool) {
+	tagValue, ok := curField.Tag.Lookup(opts.tagKey())
+	if opts.ignoreTags {
+		tagValue, ok = "", false
+	}
+	if rules, provided := opts.rules[curField.Name]; provided {
+		tagValue, ok = rules, true
+	}
+	if !ok && curField.IsExported() {
+		tagValue, ok = opts.defaults[indirectKind(curField.Type)]
+	}
+	return tagValue, ok
+}
+
+// validateField returns the violations found in the given field, in the order of its rules.
+// A non-nil error means the validation could not be performed at all.
+func validateField(parent reflect.Value, i int, opts Options) (ValidationErrors, error) {
+	curField := parent.Type().Field(i)
+	value := parent.Field(i)
+	tagValue, ok := fieldRules(curField, opts)
+	if !ok {
+		return nil, nil
+	} else if !curField.IsExported() {
+		if !opts.unexported {
+			return ValidationErrors{{Err: ErrValidateForUnexportedFields, Field: curField.Name, alias: curField.Tag.Get(nameTagKey)}}, nil
+		}
+		value = reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
+	}
+	if opts.RespectJSONOmitempty && value.IsZero() && hasJSONOmitempty(curField.Tag) {
+		return nil, nil
+	}
+	rules, err := parseRules(tagValue)
+	if err == nil && opts.resolve != nil {
+		err = resolveRules(rules, opts.resolve)
+	}
+	if err == nil {
+		rules, err = selectRules(rules, opts.flags, parent)
+	}
+	if err != nil {
+		return ValidationErrors{{Err: err, Field: curField.Name, alias: curField.Tag.Get(nameTagKey)}}, nil
+	}
+	severity, err := fieldSeverity(rules)
+	if err != nil {
+		return ValidationErrors{{Err: err, Field: curField.Name, alias: curField.Tag.Get(nameTagKey)}}, nil
+	}
+	levels, err := diveLevels(rules)
+	if err == nil && opts.RejectDuplicateRules {
+		err = checkDuplicateRules(levels)
+	}
+	if err != nil {
+		return ValidationErrors{{Err: err, Field: curField.Name, alias: curField.Tag.Get(nameTagKey)}}, nil
+	}
+	ptr := value
+	value, isNil := deref(value)
+	if normalize, ok := opts.Normalizers[curField.Name]; ok {
+		value = normalized(value, normalize)
+	}
+	fc := FieldContext{Value: value, Name: curField.Name, Tag: curField.Tag, Parent: parent}
+	vs, err := checkValue(fc, ptr, isNil, levels, "", opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vs {
+		vs[i].Field = curField.Name
+		vs[i].alias = curField.Tag.Get(nameTagKey)
+		vs[i].Severity = severity
+	}
+	return vs, nil
+}
+
+// indirectKind returns the kind of t, or of the type it points to through any number of pointers.
+func indirectKind(t reflect.Type) reflect.Kind {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
+
+// deref returns the value v points to, through any number of pointers, and whether it ends with a nil one.
+// Pointers are checked by the value they point to, and nil ones by the presence rules only. The sql.Null*
+// types work the same, an invalid one being like a nil pointer.
+func deref(v reflect.Value) (reflect.Value, bool) {
+	for {
+		switch {
+		case v.Kind() == reflect.Pointer && v.Type() != bigIntPtrType && v.Type() != bigFloatPtrType:
+			if v.IsNil() {
+				return v, true
+			}
+			v = v.Elem()
+		case isSQLNull(v.Type()):
+			if !v.Field(1).Bool() {
+				return v, true
 			}
+			v = v.Field(0)
+		default:
+			return v, false
 		}
-		return true, nil
-	default:
-		return false, ValidationError{ErrInvalidValidatorSyntax}
 	}
 }
 
-func validateBetween(v reflect.Value, value string) (bool, error) {
-	limits := strings.Split(value, ",")
-	min, err := strconv.Atoi(limits[0])
-	max, err := strconv.Atoi(limits[1])
+// isSQLNull reports whether t is one of the sql.Null* types, e.g. sql.NullString, which hold a value
+// in their first field and whether it is set in their Valid field.
+func isSQLNull(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" && strings.HasPrefix(t.Name(), "Null") &&
+		t.NumField() == 2 && t.Field(1).Name == "Valid" && t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// checkValue applies the rules of levels[0] to the value of fc, dereferenced from ptr, and the ones
+// of the deeper levels to its elements, see diveLevels. path is the position of the value in the field,
+// e.g. "[1][2]", which prefixes the messages of the violations found below the field itself.
+func checkValue(fc FieldContext, ptr reflect.Value, isNil bool, levels []diveLevel, path string, opts Options) (ValidationErrors, error) {
+	vs, err := applyRules(fc, ptr, isNil, levels[0].rules, path, opts)
 	if err != nil {
-		return false, ValidationError{ErrInvalidValidatorSyntax}
+		return nil, err
 	}
-	switch v.Interface().(type) {
-	case string:
-		if min <= len(v.String()) && len(v.String()) <= max {
-			return true, nil
-		} else {
-			return false, ValidationError{errors.New("String length is not allowed")}
+	if len(levels) == 1 || isNil {
+		return vs, nil
+	}
+	value := fc.Value
+	// keys are nil except for maps, whose entries are checked in the order of their keys
+	var keys, elems []reflect.Value
+	var paths []string
+	switch {
+	case value.Kind() == reflect.Map:
+		keys = sortedKeys(value)
+		for _, key := range keys {
+			elems = append(elems, value.MapIndex(key))
+			paths = append(paths, path+"["+fmt.Sprint(key)+"]")
 		}
-	case int:
-		if int64(min) <= v.Int() && v.Int() <= int64(max) {
-			return true, nil
-		} else {
-			return false, ValidationError{errors.New("Integer is more than allowed")}
+	case (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && len(levels[1].keys) == 0:
+		for i := 0; i < value.Len(); i++ {
+			elems = append(elems, value.Index(i))
+			paths = append(paths, path+"["+strconv.Itoa(i)+"]")
 		}
-	case []int:
-		var slice []int
-		var ok bool
-		if slice, ok = v.Interface().([]int); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+	default:
+		violation := unsupportedType(value)
+		violation.Rule, violation.Path = diveModifier, path
+		if path != "" {
+			violation.Err = errors.Wrap(violation.Err, path)
 		}
-		for i, elem := range slice {
-			if elem > max || elem < min {
-				return false, ValidationError{errors.Errorf("The integer on position %d is more than allowed", i)}
+		return append(vs, violation), nil
+	}
+	for i, elem := range elems {
+		if keys != nil {
+			keyFc := fc
+			keyFc.Value = keys[i]
+			keyVs, err := applyRules(keyFc, keys[i], false, levels[1].keys, paths[i], opts)
+			if err != nil {
+				return nil, err
 			}
+			vs = append(vs, keyVs...)
 		}
-		return true, nil
-	case []string:
-		var slice []string
-		var ok bool
-		if slice, ok = v.Interface().([]string); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
-		}
-		for i, elem := range slice {
-			if len(elem) > max || len(elem) < min {
-				return false, ValidationError{errors.Errorf("The string on position %d is longer than allowed", i)}
-			}
+		elemFc := fc
+		elemFc.Value, isNil = deref(elem)
+		elemVs, err := checkValue(elemFc, elem, isNil, levels[1:], paths[i], opts)
+		if err != nil {
+			return nil, err
 		}
-		return true, nil
-	default:
-		return false, ValidationError{ErrInvalidValidatorSyntax}
+		vs = append(vs, elemVs...)
 	}
+	return vs, nil
 }
 
-func validateMax(v reflect.Value, value string) (bool, error) {
-	max, err := strconv.Atoi(value)
-	if err != nil {
-		return false, ValidationError{ErrInvalidValidatorSyntax}
+// sortedKeys returns the keys of the map v in a stable order: integer keys in numeric order, so that 9
+// comes before 10, and the other ones in the order of their fmt form.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	switch k := v.Type().Key().Kind(); {
+	case isIntKind(k):
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	case isUintKind(k):
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() })
+	default:
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
 	}
-	switch v.Interface().(type) {
-	case string:
-		if len(v.String()) <= max {
-			return true, nil
-		} else {
-			return false, ValidationError{errors.New("String length is more than allowed")}
+	return keys
+}
+
+// applyRules returns the violations of rules by the value of fc, see checkValue.
+func applyRules(fc FieldContext, ptr reflect.Value, isNil bool, rules []rule, path string, opts Options) (ValidationErrors, error) {
+	var vs ValidationErrors
+	for _, r := range rules {
+		if _, ok := modifiers[r.name]; ok {
+			continue
 		}
-	case int:
-		if v.Int() <= int64(max) {
-			return true, nil
-		} else {
-			return false, ValidationError{errors.New("Integer is more than allowed")}
+		_, isPresenceRule := presenceRules[r.name]
+		if isNil && !isPresenceRule {
+			continue
 		}
-	case []int:
-		var slice []int
-		var ok bool
-		if slice, ok = v.Interface().([]int); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+		ruleFc := fc
+		if isPresenceRule {
+			ruleFc.Value = ptr
 		}
-		for i, elem := range slice {
-			if elem > max {
-				return false, ValidationError{errors.Errorf("The integer on position %d is more than allowed", i)}
+		validationErr, err := applyRule(ruleFc, r, opts)
+		if err != nil {
+			return nil, err
+		}
+		if validationErr != nil {
+			if path != "" {
+				validationErr.Path = path
+				validationErr.Err = errors.Wrap(validationErr.Err, path)
 			}
+			vs = append(vs, *validationErr)
 		}
-		return true, nil
-	case []string:
-		var slice []string
-		var ok bool
-		if slice, ok = v.Interface().([]string); !ok {
-			return false, ValidationError{ErrInvalidValidatorSyntax}
+	}
+	return vs, nil
+}
+
+// applyRule returns the violation of a single rule by the field, if any.
+func applyRule(fc FieldContext, r rule, opts Options) (*ValidationError, error) {
+	if len(r.alternatives) != 0 {
+		return applyAlternatives(fc, r.alternatives, opts)
+	}
+	if r.name == "in" && len(r.arg) == 0 && opts.EmptyInIsSyntaxError {
+		return &ValidationError{Err: ErrInvalidValidatorSyntax, Rule: r.name}, nil
+	}
+	value := fc.Value
+	// fallback is the value to check instead if the rule can't check value itself, see ruleError
+	var fallback reflect.Value
+	if _, ok := textValidators[r.name]; ok {
+		text, ok, err := marshalText(value)
+		if err != nil {
+			return &ValidationError{Err: errors.Wrap(err, "Field can't be marshaled to text"), Rule: r.name}, nil
+		} else if ok {
+			value = text
+		} else if str, ok := stringForm(value); ok {
+			// a Stringer of another kind is checked as such if it can be, e.g. a status int by "in:0,1",
+			// and else by its String form, e.g. by "in:Active,Inactive"
+			fallback = str
 		}
-		for i, elem := range slice {
-			if len(elem) > max {
-				return false, ValidationError{errors.Errorf("The string on position %d is longer than allowed", i)}
-			}
+	}
+	if isByteString(value) {
+		_, isText := textValidators[r.name]
+		_, isByteString := byteStringValidators[r.name]
+		switch {
+		case r.name == "in":
+			// in checks the bytes one by one, like the elements of other integer slices, unless
+			// its tokens aren't integers, as in "in:pdf,png"
+			fallback = reflect.ValueOf(string(value.Bytes()))
+		case isText || isByteString:
+			value = reflect.ValueOf(string(value.Bytes()))
 		}
-		return true, nil
-	default:
-		return false, ValidationError{ErrInvalidValidatorSyntax}
 	}
+	validator, ok := lookupValidator(opts.ctx, r.name)
+	if !ok {
+		fieldValidator, ok := lookupFieldValidator(r.name)
+		if !ok {
+			return &ValidationError{Err: errors.New("Unexpected validator option"), Rule: r.name}, nil
+		}
+		fc.Value = value
+		validator = func(_ reflect.Value, value string) (bool, error) {
+			fc.Arg = value
+			return fieldValidator(fc)
+		}
+	}
+	if r.name == "between" && opts.AllowReversedRange && IsBuiltin(r.name) {
+		validator = func(v reflect.Value, value string) (bool, error) {
+			return checkBetween(v, value, true)
+		}
+	}
+	var start time.Time
+	if opts.OnValidatorRun != nil {
+		start = time.Now()
+	}
+	ok, err := validator(value, r.arg)
+	if !ok && fallback.IsValid() && ruleError(err) {
+		value = fallback
+		ok, err = validator(value, r.arg)
+	}
+	if opts.OnValidatorRun != nil {
+		opts.OnValidatorRun(r.name, time.Since(start))
+	}
+	if !ok {
+		if err == nil {
+			err = ValidationError{Err: errors.Errorf("Field doesn't satisfy the %s rule", r.name)}
+		}
+		if validationErr, isValidationErr := err.(ValidationError); !isValidationErr {
+			return nil, err
+		} else {
+			// изначально было вот так:
+			// return &ValidationError{Err: fmt.Errorf("\"%s\" field validation failed: %w", curField.Name, validationErr)}, nil
+			// но некоторые тесты требуют жёсткого совпадения текста ошибок: оборачивать их не получается
+			if opts.Translator != nil && !isRuleError(validationErr.Err) {
+				if msg := opts.Translator(r.name, fc.Name); msg != "" {
+					validationErr.Err = errors.New(msg)
+				}
+			}
+			if opts.IncludeValue && !isRuleError(validationErr.Err) {
+				validationErr.Err = withValue(validationErr, value)
+			}
+			validationErr.Rule = r.name
+			return &validationErr, nil
+		}
+	}
+	return nil, nil
+}
+
+// applyAlternatives returns a violation combining the ones of all alternatives, unless one of them passes.
+func applyAlternatives(fc FieldContext, alternatives []rule, opts Options) (*ValidationError, error) {
+	messages := make([]string, 0, len(alternatives))
+	names := make([]string, 0, len(alternatives))
+	for _, alternative := range alternatives {
+		validationErr, err := applyRule(fc, alternative, opts)
+		if err != nil {
+			return nil, err
+		}
+		if validationErr == nil {
+			return nil, nil
+		}
+		messages = append(messages, validationErr.Error())
+		names = append(names, validationErr.Rule)
+	}
+	return &ValidationError{Err: errors.New(strings.Join(messages, " or ")), Rule: strings.Join(names, "|")}, nil
+}
+
+// marshalText returns the MarshalText form of v as a string value, if v implements encoding.TextMarshaler.
+func marshalText(v reflect.Value) (reflect.Value, bool, error) {
+	var marshaler encoding.TextMarshaler
+	switch {
+	case v.Type().Implements(textMarshalerType):
+		if isNilable(v.Kind()) && v.IsNil() {
+			return v, false, nil
+		}
+		value, ok := readable(v)
+		if !ok {
+			return v, false, ErrValidateForUnexportedFields
+		}
+		marshaler = value.Interface().(encoding.TextMarshaler)
+	case reflect.PointerTo(v.Type()).Implements(textMarshalerType):
+		value, ok := readable(v)
+		if !ok {
+			return v, false, ErrValidateForUnexportedFields
+		}
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(value)
+		marshaler = ptr.Interface().(encoding.TextMarshaler)
+	default:
+		return v, false, nil
+	}
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		return v, false, err
+	}
+	return reflect.ValueOf(string(text)), true, nil
+}
+
+// readable returns v if it can be read through Interface, or else a copy of it made with the Kind accessors,
+// which also work on the values reached through unexported fields. It reports false if v is of another kind
+// than a bool, number or string, e.g. a time.Time, which can't be copied that way.
+func readable(v reflect.Value) (reflect.Value, bool) {
+	if v.CanInterface() {
+		return v, true
+	}
+	c := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Bool:
+		c.SetBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.SetInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		c.SetUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		c.SetFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c.SetComplex(v.Complex())
+	case reflect.String:
+		c.SetString(v.String())
+	default:
+		return v, false
+	}
+	return c, true
+}
+
+// isByteString reports whether v is a []byte, or a slice of another byte type.
+func isByteString(v reflect.Value) bool {
+	return elemKind(v) == reflect.Uint8
+}
+
+// stringForm returns the String form of v as a string value, if v is not a string but implements fmt.Stringer,
+// or the String forms of the elements of v as a []string value, if v is a slice of such values.
+func stringForm(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.String && v.Type().Elem().Implements(stringerType) {
+		res := make([]string, v.Len())
+		for i := range res {
+			str, ok := stringForm(v.Index(i))
+			if !ok {
+				return v, false
+			}
+			res[i] = str.String()
+		}
+		return reflect.ValueOf(res), true
+	}
+	if v.Kind() == reflect.String || !v.Type().Implements(stringerType) {
+		return v, false
+	}
+	if isNilable(v.Kind()) && v.IsNil() {
+		return v, false
+	}
+	value, ok := readable(v)
+	if !ok {
+		return v, false
+	}
+	return reflect.ValueOf(value.Interface().(fmt.Stringer).String()), true
+}
+
+// normalized returns a copy of the string or string slice v transformed by normalize, or v itself for other values.
+func normalized(v reflect.Value, normalize func(string) string) reflect.Value {
+	switch {
+	case v.Kind() == reflect.String:
+		return reflect.ValueOf(normalize(v.String()))
+	case elemKind(v) == reflect.String:
+		res := make([]string, v.Len())
+		for i := range res {
+			res[i] = normalize(v.Index(i).String())
+		}
+		return reflect.ValueOf(res)
+	default:
+		return v
+	}
+}
+
+// withValue appends the offending value to the violation message.
+func withValue(ve ValidationError, v reflect.Value) error {
+	if ve.elem.IsValid() {
+		return fmt.Errorf("%w (index %d: %v)", ve.Err, ve.index, ve.elem)
+	}
+	return fmt.Errorf("%w (value: %v)", ve.Err, v)
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUintKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// elemKind returns the kind of the elements of a slice, or reflect.Invalid for other values.
+func elemKind(v reflect.Value) reflect.Kind {
+	if v.Kind() != reflect.Slice {
+		return reflect.Invalid
+	}
+	return v.Type().Elem().Kind()
+}
+
+// validateLen requires a string of the given length in bytes, a slice of such strings, or a map of that many entries.
+func validateLen(v reflect.Value, value string) (bool, error) {
+	expected, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if len(v.String()) != expected {
+			return false, ValidationError{Err: errors.New("lengths don't match")}
+		}
+		return true, nil
+	case v.Kind() == reflect.Map:
+		if v.Len() != expected {
+			return false, ValidationError{Err: errors.Errorf("Map has %d entries, expected %d", v.Len(), expected)}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if len(v.Index(i).String()) != expected {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is shorter than allowed", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// validateNotIn is the negation of validateIn, for "in:!admin,root", which rejects the listed values
+// and, for slices, the elements with one of them.
+func validateNotIn(v reflect.Value, value string) (bool, error) {
+	if len(value) == 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if v.Kind() != reflect.Slice {
+		return notIn(v, value, ValidationError{Err: errors.New("Field value isn't allowed")})
+	}
+	kind := "integer"
+	if elemKind(v) == reflect.String {
+		kind = "string"
+	}
+	for i := 0; i < v.Len(); i++ {
+		if ok, err := notIn(v.Index(i), value, elemError(v, i, errors.Errorf("The %s on position %d is not allowed", kind, i))); !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// notIn reports whether v is not one of the values of an in rule, returning violation if it is.
+func notIn(v reflect.Value, value string, violation ValidationError) (bool, error) {
+	ok, err := validateIn(v, value)
+	if ok {
+		return false, violation
+	}
+	if validationErr, isValidationErr := err.(ValidationError); isValidationErr && isRuleError(validationErr.Err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// parseIntRanges parses the tokens of in for integers, which are either numbers or inclusive ranges
+// of numbers like "1-5". The "-" of a range is the first one after the first character, so negative
+// numbers stay unambiguous: "-3" is a number and "-5--3" the range from -5 to -3.
+func parseIntRanges(tokens []string) ([][2]int64, bool) {
+	res := make([][2]int64, 0, len(tokens))
+	for _, token := range tokens {
+		from, to := token, token
+		if len(token) > 1 {
+			if i := strings.IndexByte(token[1:], '-'); i >= 0 {
+				from, to = token[:i+1], token[i+2:]
+			}
+		}
+		lo, loErr := strconv.ParseInt(from, 10, 64)
+		hi, hiErr := strconv.ParseInt(to, 10, 64)
+		if loErr != nil || hiErr != nil || lo > hi {
+			return nil, false
+		}
+		res = append(res, [2]int64{lo, hi})
+	}
+	return res, true
+}
+
+func inIntRanges(x int64, ranges [][2]int64) bool {
+	for _, r := range ranges {
+		if r[0] <= x && x <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIn requires one of the ","-separated values of the rule, e.g. "in:admin,user", or of the set registered
+// with RegisterSetFromReader under the name following "@", e.g. "in:@roles"; "in:!..." rejects them instead.
+// The values may be quoted, so that "in:'a,b',c" allows "a,b", and "in:'@home','@work'" the literal "@home".
+func validateIn(v reflect.Value, value string) (bool, error) {
+	if len(value) == 0 {
+		return false, ValidationError{Err: errors.New("Field value isn't allowed")}
+	}
+	if strings.HasPrefix(value, "!") {
+		return validateNotIn(v, value[1:])
+	}
+	var tokens []string
+	var tokensSet map[string]struct{}
+	if name, ok := strings.CutPrefix(value, "@"); ok {
+		set, ok := lookupSet(name)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		tokens, tokensSet = set.values, set.index
+	} else {
+		// the tokens are trimmed, so that "in:5, 7" means "in:5,7", and may be quoted, as in "in:'a,b',c"
+		tokens, ok = splitList(value, true)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		tokensSet = make(map[string]struct{})
+		for _, elem := range tokens {
+			tokensSet[elem] = struct{}{}
+		}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if _, ok := tokensSet[v.String()]; ok {
+			return true, nil
+		}
+		return false, ValidationError{Err: errors.New("Field value isn't allowed")}
+	case isIntKind(v.Kind()):
+		ranges, ok := parseIntRanges(tokens)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if inIntRanges(v.Int(), ranges) {
+			return true, nil
+		}
+		return false, ValidationError{Err: errors.New("Field value isn't allowed")}
+	case isUintKind(v.Kind()):
+		allowed := false
+		for key := range tokensSet {
+			val, err := strconv.ParseUint(key, 10, 64)
+			if err != nil {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			allowed = allowed || val == v.Uint()
+		}
+		if allowed {
+			return true, nil
+		}
+		return false, ValidationError{Err: errors.New("Field value isn't allowed")}
+	case v.Kind() == reflect.Bool:
+		allowed := false
+		for key := range tokensSet {
+			val, err := strconv.ParseBool(key)
+			if err != nil {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			allowed = allowed || val == v.Bool()
+		}
+		if allowed {
+			return true, nil
+		}
+		return false, ValidationError{Err: errors.New("Field value isn't allowed")}
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if _, ok := tokensSet[v.Index(i).String()]; !ok {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is not allowed", i))
+			}
+		}
+		return true, nil
+	case isIntKind(elemKind(v)):
+		ranges, ok := parseIntRanges(tokens)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		for i := 0; i < v.Len(); i++ {
+			if !inIntRanges(v.Index(i).Int(), ranges) {
+				return false, elemError(v, i, errors.Errorf("The integer on position %d is less than allowed", i))
+			}
+		}
+		return true, nil
+	case isUintKind(elemKind(v)):
+		tokensSetUint := make(map[uint64]struct{})
+		for elem := range tokensSet {
+			elemUint, err := strconv.ParseUint(elem, 10, 64)
+			if err != nil {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			tokensSetUint[elemUint] = struct{}{}
+		}
+		for i := 0; i < v.Len(); i++ {
+			if _, ok := tokensSetUint[v.Index(i).Uint()]; !ok {
+				return false, elemError(v, i, errors.Errorf("The integer on position %d is not allowed", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// validateMin checks the length of strings, the value of integers and, element-wise, slices of them.
+// For maps it checks the number of entries.
+func validateMin(v reflect.Value, value string) (bool, error) {
+	if v.Type() == bigIntPtrType {
+		bounds, ok := parseBigInts(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateBigInt(v, bounds[0], nil)
+	}
+	if v.Type() == bigFloatPtrType {
+		bounds, ok := parseBigFloats(bigFloatPrec(v), value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateBigFloat(v, bounds[0], nil)
+	}
+	if v.Type() == durationType {
+		bounds, ok := parseDurations(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateDuration(v, &bounds[0], nil)
+	}
+	if v.Type() == timeType {
+		bounds, ok := parseTimes(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateTime(v, &bounds[0], nil, false)
+	}
+	if v.Type() == jsonNumberType {
+		return validateJSONNumber(v, &value, nil)
+	}
+	if v.Type() == netipAddrType {
+		bounds, ok := parseAddrs(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateAddr(v, &bounds[0], nil)
+	}
+	if isFloatKind(v.Kind()) || isFloatKind(elemKind(v)) {
+		bounds, ok := parseFloats(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateFloat(v, &bounds[0], nil)
+	}
+	min, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if len(v.String()) >= min {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("String length is less than allowed")}
+		}
+	case isIntKind(v.Kind()):
+		if v.Int() >= int64(min) {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("Integer is less than allowed")}
+		}
+	case v.Kind() == reflect.Map:
+		if v.Len() >= min {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("Map has fewer entries than allowed")}
+		}
+	case isIntKind(elemKind(v)):
+		for i := 0; i < v.Len(); i++ {
+			if v.Index(i).Int() < int64(min) {
+				return false, elemError(v, i, errors.Errorf("The integer on position %d is less than allowed", i))
+			}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if len(v.Index(i).String()) < min {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is shorter than allowed", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+func validateBetween(v reflect.Value, value string) (bool, error) {
+	return checkBetween(v, value, false)
+}
+
+// checkBetween is validateBetween, which rejects reversed bounds such as "between:100,0" with
+// ErrInvalidValidatorSyntax, unless allowReversed is set, swapping them instead.
+func checkBetween(v reflect.Value, value string, allowReversed bool) (bool, error) {
+	limits := strings.Split(value, ",")
+	if v.Type() == bigIntPtrType {
+		bounds, ok := parseBigInts(limits...)
+		if !ok || len(bounds) != 2 {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if bounds[0].Cmp(bounds[1]) > 0 {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			bounds[0], bounds[1] = bounds[1], bounds[0]
+		}
+		return validateBigInt(v, bounds[0], bounds[1])
+	}
+	if v.Type() == bigFloatPtrType {
+		bounds, ok := parseBigFloats(bigFloatPrec(v), limits...)
+		if !ok || len(bounds) != 2 {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if bounds[0].Cmp(bounds[1]) > 0 {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			bounds[0], bounds[1] = bounds[1], bounds[0]
+		}
+		return validateBigFloat(v, bounds[0], bounds[1])
+	}
+	if v.Type() == durationType {
+		bounds, ok := parseDurations(limits...)
+		if !ok || len(bounds) != 2 {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if bounds[0] > bounds[1] {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			bounds[0], bounds[1] = bounds[1], bounds[0]
+		}
+		return validateDuration(v, &bounds[0], &bounds[1])
+	}
+	if v.Type() == timeType {
+		bounds, exclusive, ok := parseTimeBetween(limits)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if bounds[0].After(bounds[1]) {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			bounds[0], bounds[1] = bounds[1], bounds[0]
+		}
+		return validateTime(v, &bounds[0], &bounds[1], exclusive)
+	}
+	if v.Type() == jsonNumberType {
+		if len(limits) != 2 {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if jsonBoundsReversed(limits[0], limits[1]) {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			limits[0], limits[1] = limits[1], limits[0]
+		}
+		return validateJSONNumber(v, &limits[0], &limits[1])
+	}
+	if v.Type() == netipAddrType {
+		bounds, ok := parseAddrs(limits...)
+		if !ok || len(bounds) != 2 {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if bounds[0].Compare(bounds[1]) > 0 {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			bounds[0], bounds[1] = bounds[1], bounds[0]
+		}
+		return validateAddr(v, &bounds[0], &bounds[1])
+	}
+	if isFloatKind(v.Kind()) || isFloatKind(elemKind(v)) {
+		bounds, ok := parseFloats(limits...)
+		if !ok || len(bounds) != 2 {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if bounds[0] > bounds[1] {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			bounds[0], bounds[1] = bounds[1], bounds[0]
+		}
+		return validateFloat(v, &bounds[0], &bounds[1])
+	}
+	if len(limits) != 2 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if isUintKind(v.Kind()) || isUintKind(elemKind(v)) {
+		min, minErr := strconv.ParseUint(limits[0], 10, 64)
+		max, maxErr := strconv.ParseUint(limits[1], 10, 64)
+		if minErr != nil || maxErr != nil {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		if min > max {
+			if !allowReversed {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			min, max = max, min
+		}
+		return validateUintBetween(v, min, max)
+	}
+	min, minErr := strconv.Atoi(limits[0])
+	max, maxErr := strconv.Atoi(limits[1])
+	if minErr != nil || maxErr != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if min > max {
+		if !allowReversed {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		min, max = max, min
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if min <= len(v.String()) && len(v.String()) <= max {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("String length is not allowed")}
+		}
+	case isIntKind(v.Kind()):
+		if int64(min) <= v.Int() && v.Int() <= int64(max) {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("Integer is more than allowed")}
+		}
+	case isIntKind(elemKind(v)):
+		for i := 0; i < v.Len(); i++ {
+			if elem := v.Index(i).Int(); elem > int64(max) || elem < int64(min) {
+				return false, elemError(v, i, errors.Errorf("The integer on position %d is more than allowed", i))
+			}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if elem := v.Index(i).String(); len(elem) > max || len(elem) < min {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is longer than allowed", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// validateUintBetween checks an unsigned integer, or each element of a slice of them, against the inclusive bounds.
+func validateUintBetween(v reflect.Value, min, max uint64) (bool, error) {
+	if isUintKind(elemKind(v)) {
+		for i := 0; i < v.Len(); i++ {
+			if elem := v.Index(i).Uint(); elem < min {
+				return false, elemError(v, i, errors.Errorf("The integer on position %d is less than allowed", i))
+			} else if elem > max {
+				return false, elemError(v, i, errors.Errorf("The integer on position %d is more than allowed", i))
+			}
+		}
+		return true, nil
+	}
+	if v.Uint() < min {
+		return false, ValidationError{Err: errors.New("Integer is less than allowed")}
+	} else if v.Uint() > max {
+		return false, ValidationError{Err: errors.New("Integer is more than allowed")}
+	}
+	return true, nil
+}
+
+// validateMax is the upper-bound counterpart of validateMin.
+func validateMax(v reflect.Value, value string) (bool, error) {
+	if v.Type() == bigIntPtrType {
+		bounds, ok := parseBigInts(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateBigInt(v, nil, bounds[0])
+	}
+	if v.Type() == bigFloatPtrType {
+		bounds, ok := parseBigFloats(bigFloatPrec(v), value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateBigFloat(v, nil, bounds[0])
+	}
+	if v.Type() == durationType {
+		bounds, ok := parseDurations(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateDuration(v, nil, &bounds[0])
+	}
+	if v.Type() == timeType {
+		bounds, ok := parseTimes(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateTime(v, nil, &bounds[0], false)
+	}
+	if v.Type() == jsonNumberType {
+		return validateJSONNumber(v, nil, &value)
+	}
+	if v.Type() == netipAddrType {
+		bounds, ok := parseAddrs(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateAddr(v, nil, &bounds[0])
+	}
+	if isFloatKind(v.Kind()) || isFloatKind(elemKind(v)) {
+		bounds, ok := parseFloats(value)
+		if !ok {
+			return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+		}
+		return validateFloat(v, nil, &bounds[0])
+	}
+	max, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if len(v.String()) <= max {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("String length is more than allowed")}
+		}
+	case isIntKind(v.Kind()):
+		if v.Int() <= int64(max) {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("Integer is more than allowed")}
+		}
+	case v.Kind() == reflect.Map:
+		if v.Len() <= max {
+			return true, nil
+		} else {
+			return false, ValidationError{Err: errors.New("Map has more entries than allowed")}
+		}
+	case isIntKind(elemKind(v)):
+		for i := 0; i < v.Len(); i++ {
+			if v.Index(i).Int() > int64(max) {
+				return false, elemError(v, i, errors.Errorf("The integer on position %d is more than allowed", i))
+			}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if len(v.Index(i).String()) > max {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is longer than allowed", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// validateURL accepts an optional comma-separated list of constraints:
+// bare items and scheme=... restrict the scheme, host=... restricts the host
+// (compared together with the port if the constraint has one).
+func validateURL(v reflect.Value, value string) (bool, error) {
+	schemes := make(map[string]struct{})
+	hosts := make(map[string]struct{})
+	if len(value) != 0 {
+		for _, constraint := range strings.Split(value, ",") {
+			key, val, found := strings.Cut(constraint, "=")
+			if !found {
+				key, val = "scheme", constraint
+			}
+			if len(val) == 0 {
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+			switch key {
+			case "scheme":
+				schemes[strings.ToLower(val)] = struct{}{}
+			case "host":
+				hosts[strings.ToLower(val)] = struct{}{}
+			default:
+				return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+			}
+		}
+	}
+	check := func(s string) error {
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("String is not a valid URL")
+		}
+		if _, ok := schemes[strings.ToLower(u.Scheme)]; len(schemes) != 0 && !ok {
+			return errors.Errorf("URL scheme %q is not allowed", u.Scheme)
+		}
+		if len(hosts) != 0 {
+			_, withPort := hosts[strings.ToLower(u.Host)]
+			_, withoutPort := hosts[strings.ToLower(u.Hostname())]
+			if !withPort && !withoutPort {
+				return errors.Errorf("URL host %q is not allowed", u.Host)
+			}
+		}
+		return nil
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if err := check(v.String()); err != nil {
+			return false, ValidationError{Err: err}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if err := check(v.Index(i).String()); err != nil {
+				return false, elemError(v, i, errors.Wrapf(err, "The string on position %d is not allowed", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// siblingString returns the string values of the validated field and of the sibling field named by the rule argument.
+func siblingString(fc FieldContext) (string, string, error) {
+	if fc.Value.Kind() != reflect.String {
+		return "", "", unsupportedType(fc.Value)
+	}
+	target := fc.Parent.FieldByName(fc.Arg)
+	if !target.IsValid() || target.Kind() != reflect.String {
+		return "", "", ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return fc.Value.String(), target.String(), nil
+}
+
+func validateMaxLenField(fc FieldContext) (bool, error) {
+	own, other, err := siblingString(fc)
+	if err != nil {
+		return false, err
+	}
+	if len(own) > len(other) {
+		return false, ValidationError{Err: errors.Errorf("Field %s is longer than field %s", fc.Name, fc.Arg)}
+	}
+	return true, nil
+}
+
+func validateMinLenField(fc FieldContext) (bool, error) {
+	own, other, err := siblingString(fc)
+	if err != nil {
+		return false, err
+	}
+	if len(own) < len(other) {
+		return false, ValidationError{Err: errors.Errorf("Field %s is shorter than field %s", fc.Name, fc.Arg)}
+	}
+	return true, nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// validateMethod calls the method of the struct named by the rule argument, e.g. "method:IsValid",
+// which takes no arguments and returns either a bool, false meaning a violation, or an error.
+func validateMethod(fc FieldContext) (bool, error) {
+	method := fc.Parent.MethodByName(fc.Arg)
+	if !method.IsValid() && fc.Parent.CanAddr() {
+		method = fc.Parent.Addr().MethodByName(fc.Arg)
+	}
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if !method.CanInterface() {
+		return false, ValidationError{Err: ErrValidateForUnexportedFields}
+	}
+	switch out := method.Type().Out(0); {
+	case out.Kind() == reflect.Bool:
+		if !method.Call(nil)[0].Bool() {
+			return false, ValidationError{Err: errors.Errorf("Field %s doesn't satisfy method %s", fc.Name, fc.Arg)}
+		}
+		return true, nil
+	case out == errorType:
+		if err, _ := method.Call(nil)[0].Interface().(error); err != nil {
+			return false, ValidationError{Err: err}
+		}
+		return true, nil
+	default:
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+}
+
+func validateNotBlank(v reflect.Value, value string) (bool, error) {
+	if len(value) != 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if strings.TrimSpace(v.String()) == "" {
+			return false, ValidationError{Err: errors.New("String is blank")}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if strings.TrimSpace(v.Index(i).String()) == "" {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is blank", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// countDigits returns the number of decimal digits of the absolute value of an int or uint value.
+func countDigits(v reflect.Value) (int, bool) {
+	var abs uint64
+	switch {
+	case isIntKind(v.Kind()):
+		if n := v.Int(); n < 0 {
+			abs = uint64(-(n + 1)) + 1
+		} else {
+			abs = uint64(n)
+		}
+	case isUintKind(v.Kind()):
+		abs = v.Uint()
+	default:
+		return 0, false
+	}
+	digits := 1
+	for ; abs >= 10; abs /= 10 {
+		digits++
+	}
+	return digits, true
+}
+
+// checkDigits applies check to the digit count of v, or of each element if v is a slice.
+func checkDigits(v reflect.Value, check func(digits int) error) (bool, error) {
+	if digits, ok := countDigits(v); ok {
+		if err := check(digits); err != nil {
+			return false, ValidationError{Err: err}
+		}
+		return true, nil
+	}
+	if k := elemKind(v); !isIntKind(k) && !isUintKind(k) {
+		return false, unsupportedType(v)
+	}
+	for i := 0; i < v.Len(); i++ {
+		digits, _ := countDigits(v.Index(i))
+		if err := check(digits); err != nil {
+			return false, elemError(v, i, errors.Wrapf(err, "The integer on position %d is not allowed", i))
+		}
+	}
+	return true, nil
+}
+
+func validateDigits(v reflect.Value, value string) (bool, error) {
+	expected, err := strconv.Atoi(value)
+	if err != nil || expected < 1 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return checkDigits(v, func(digits int) error {
+		if digits != expected {
+			return errors.Errorf("Integer has %d digits, expected %d", digits, expected)
+		}
+		return nil
+	})
+}
+
+func validateDigitsBetween(v reflect.Value, value string) (bool, error) {
+	limits := strings.Split(value, ",")
+	if len(limits) != 2 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	min, minErr := strconv.Atoi(limits[0])
+	max, maxErr := strconv.Atoi(limits[1])
+	if minErr != nil || maxErr != nil || min < 1 || min > max {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return checkDigits(v, func(digits int) error {
+		if digits < min || digits > max {
+			return errors.Errorf("Integer has %d digits, expected between %d and %d", digits, min, max)
+		}
+		return nil
+	})
+}
+
+func isISBN10(s string) bool {
+	if len(s) != 10 {
+		return false
+	}
+	sum := 0
+	for i, r := range s {
+		var d int
+		switch {
+		case '0' <= r && r <= '9':
+			d = int(r - '0')
+		case (r == 'X' || r == 'x') && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * d
+	}
+	return sum%11 == 0
+}
+
+func isISBN13(s string) bool {
+	if len(s) != 13 {
+		return false
+	}
+	sum := 0
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+		d := int(r - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validateISBN checks ISBN-10 and/or ISBN-13 checksums, ignoring hyphens and spaces.
+func validateISBN(v reflect.Value, value string) (bool, error) {
+	var check func(string) bool
+	var message string
+	switch value {
+	case "":
+		check = func(s string) bool { return isISBN10(s) || isISBN13(s) }
+		message = "String is not a valid ISBN"
+	case "10":
+		check = isISBN10
+		message = "String is not a valid ISBN-10"
+	case "13":
+		check = isISBN13
+		message = "String is not a valid ISBN-13"
+	default:
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	normalize := strings.NewReplacer("-", "", " ", "")
+	switch {
+	case v.Kind() == reflect.String:
+		if !check(normalize.Replace(v.String())) {
+			return false, ValidationError{Err: errors.New(message)}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if !check(normalize.Replace(v.Index(i).String())) {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is not a valid ISBN", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// hasLen reports whether v has a length: it is a string, slice, array or map.
+func hasLen(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateLenMin bounds the length of the value itself, unlike min which checks
+// the elements of slices one by one.
+func validateLenMin(v reflect.Value, value string) (bool, error) {
+	min, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if !hasLen(v) {
+		return false, unsupportedType(v)
+	}
+	if v.Len() < min {
+		return false, ValidationError{Err: errors.Errorf("Length %d is less than allowed %d", v.Len(), min)}
+	}
+	return true, nil
+}
+
+// validateLenMax is the upper-bound counterpart of validateLenMin.
+func validateLenMax(v reflect.Value, value string) (bool, error) {
+	max, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if !hasLen(v) {
+		return false, unsupportedType(v)
+	}
+	if v.Len() > max {
+		return false, ValidationError{Err: errors.Errorf("Length %d is more than allowed %d", v.Len(), max)}
+	}
+	return true, nil
+}
+
+// checkEmailDomain returns the reason why s is not an email address with one of the domains, if any.
+func checkEmailDomain(s string, domains map[string]struct{}) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return errors.Errorf("%q is not a valid email address", s)
+	}
+	domain := addr.Address[strings.LastIndexByte(addr.Address, '@')+1:]
+	if _, ok := domains[strings.ToLower(domain)]; !ok {
+		return errors.Errorf("Email domain %q is not allowed", domain)
+	}
+	return nil
+}
+
+// validateEmailDomain requires an email address with one of the domains of its argument,
+// compared case-insensitively, e.g. "emaildomain:example.com,corp.com".
+func validateEmailDomain(v reflect.Value, value string) (bool, error) {
+	if len(value) == 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	domains := make(map[string]struct{})
+	for _, domain := range strings.Split(value, ",") {
+		domains[strings.ToLower(domain)] = struct{}{}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if err := checkEmailDomain(v.String(), domains); err != nil {
+			return false, ValidationError{Err: err}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkEmailDomain(v.Index(i).String(), domains); err != nil {
+				return false, elemError(v, i, errors.Wrapf(err, "The string on position %d is not allowed", i))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// validateBitmask allows only the bits of a mask to be set in an integer, e.g. "bitmask:0b111"; with
+// "bitmask:require,0b111", at least one of them must also be set. The mask may use any prefix of Go
+// integer literals.
+func validateBitmask(v reflect.Value, value string) (bool, error) {
+	maskValue, require := value, false
+	if rest, ok := strings.CutPrefix(value, "require,"); ok {
+		maskValue, require = rest, true
+	}
+	mask, err := strconv.ParseUint(maskValue, 0, 64)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	var bits uint64
+	switch {
+	case isIntKind(v.Kind()):
+		bits = uint64(v.Int())
+	case isUintKind(v.Kind()):
+		bits = v.Uint()
+	default:
+		return false, unsupportedType(v)
+	}
+	if extra := bits &^ mask; extra != 0 {
+		return false, ValidationError{Err: errors.Errorf("Bits %#b are not allowed by mask %#b", extra, mask)}
+	}
+	if require && bits&mask == 0 {
+		return false, ValidationError{Err: errors.Errorf("None of the bits of mask %#b is set", mask)}
+	}
+	return true, nil
+}
+
+// validateCapMin bounds the capacity of a slice, i.e. the memory it holds on to, unlike lenmin
+// which bounds the number of elements in use.
+func validateCapMin(v reflect.Value, value string) (bool, error) {
+	min, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if v.Kind() != reflect.Slice {
+		return false, unsupportedType(v)
+	}
+	if v.Cap() < min {
+		return false, ValidationError{Err: errors.Errorf("Capacity %d is less than allowed %d", v.Cap(), min)}
+	}
+	return true, nil
+}
+
+// validateCapMax is the upper-bound counterpart of validateCapMin, e.g. "capmax:4096" for a reused buffer.
+func validateCapMax(v reflect.Value, value string) (bool, error) {
+	max, err := strconv.Atoi(value)
+	if err != nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if v.Kind() != reflect.Slice {
+		return false, unsupportedType(v)
+	}
+	if v.Cap() > max {
+		return false, ValidationError{Err: errors.Errorf("Capacity %d is more than allowed %d", v.Cap(), max)}
+	}
+	return true, nil
+}
+
+// parseApprox parses the "value,epsilon" argument of eqapprox and neapprox.
+func parseApprox(value string) (float64, float64, bool) {
+	target, epsilon, found := strings.Cut(value, ",")
+	if !found {
+		return 0, 0, false
+	}
+	t, err := strconv.ParseFloat(target, 64)
+	if err != nil || math.IsNaN(t) {
+		return 0, 0, false
+	}
+	e, err := strconv.ParseFloat(epsilon, 64)
+	if err != nil || e < 0 || math.IsNaN(e) {
+		return 0, 0, false
+	}
+	return t, e, true
+}
+
+// checkApprox reports a violation of v, or of one of its elements, for which equal(|v - target| <= epsilon) is false.
+// NaN and infinite values are rejected as such, as in validateFloat.
+func checkApprox(v reflect.Value, value string, want bool, message string) (bool, error) {
+	target, epsilon, ok := parseApprox(value)
+	if !ok {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	switch {
+	case isFloatKind(v.Kind()):
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return false, ValidationError{Err: errors.New("Number is not a finite number")}
+		}
+		if (math.Abs(v.Float()-target) <= epsilon) != want {
+			return false, ValidationError{Err: errors.Errorf(message, target)}
+		}
+		return true, nil
+	case isFloatKind(elemKind(v)):
+		for i := 0; i < v.Len(); i++ {
+			f := v.Index(i).Float()
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return false, elemError(v, i, errors.Errorf("The number on position %d is not a finite number", i))
+			}
+			if (math.Abs(f-target) <= epsilon) != want {
+				return false, elemError(v, i, errors.Errorf("The float on position %d is not allowed: "+message, i, target))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+func validateEqApprox(v reflect.Value, value string) (bool, error) {
+	return checkApprox(v, value, true, "Float is not approximately equal to %v")
+}
+
+func validateNeApprox(v reflect.Value, value string) (bool, error) {
+	return checkApprox(v, value, false, "Float is approximately equal to %v")
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// matchStrings reports a violation with message if the string v, or one of its elements, doesn't satisfy match.
+func matchStrings(v reflect.Value, match func(string) bool, message string) (bool, error) {
+	switch {
+	case v.Kind() == reflect.String:
+		if !match(v.String()) {
+			return false, ValidationError{Err: errors.New(message)}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if !match(v.Index(i).String()) {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is not allowed: %s", i, message))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+var hexColorRegexp = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateHexColor accepts colors of 3 or 6 hex digits with an optional leading '#', e.g. "#FFF" or "AABBCC".
+func validateHexColor(v reflect.Value, value string) (bool, error) {
+	if len(value) != 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if !hexColorRegexp.MatchString(v.String()) {
+			return false, ValidationError{Err: errors.Errorf("%q is not a valid hex color", v.String())}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if elem := v.Index(i).String(); !hexColorRegexp.MatchString(elem) {
+				return false, elemError(v, i, errors.Errorf("The string on position %d is not a valid hex color: %q", i, elem))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// validateUUID accepts UUIDs in the canonical 8-4-4-4-12 form.
+func validateUUID(v reflect.Value, value string) (bool, error) {
+	if len(value) != 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return matchStrings(v, uuidRegexp.MatchString, "String is not a valid UUID")
+}
+
+// validateRegexp requires strings matching the pattern, which may be quoted to hold ";" or "|", e.g. "regexp:'^(a|b)$'".
+func validateRegexp(v reflect.Value, value string) (bool, error) {
+	value = unquoteArg(value)
+	re := compileRegexp(value)
+	if re == nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return matchStrings(v, re.MatchString, fmt.Sprintf("String doesn't match %q", value))
+}
+
+// validateIRegexp is the case-insensitive version of validateRegexp, "iregexp:^abc$" meaning "regexp:(?i)^abc$".
+func validateIRegexp(v reflect.Value, value string) (bool, error) {
+	value = unquoteArg(value)
+	re := compileRegexp("(?i)" + value)
+	if re == nil {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return matchStrings(v, re.MatchString, fmt.Sprintf("String doesn't match %q case-insensitively", value))
+}
+
+// compiledRegexps caches the patterns of the regexp rules, as they are usually the same for every
+// validation of a struct type. An invalid pattern is stored as a nil *regexp.Regexp.
+var compiledRegexps sync.Map
+
+// compileRegexp returns the compiled pattern, or nil if it is invalid.
+func compileRegexp(pattern string) *regexp.Regexp {
+	if re, ok := compiledRegexps.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	compiledRegexps.Store(pattern, re)
+	return re
+}
+
+// validatePrefixAny requires a string starting with one of the prefixes of its argument, e.g. "prefixany:http://,https://".
+// The prefixes may be quoted, see splitList.
+func validatePrefixAny(v reflect.Value, value string) (bool, error) {
+	if len(value) == 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	prefixes, ok := splitList(value, false)
+	if !ok {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return matchStrings(v, func(s string) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(s, prefix) {
+				return true
+			}
+		}
+		return false
+	}, fmt.Sprintf("String doesn't start with any of %q", value))
+}
+
+// validateSuffixAny requires a string ending with one of the suffixes of its argument, e.g. "suffixany:.jpg,.png,.gif".
+// The suffixes may be quoted, see splitList.
+func validateSuffixAny(v reflect.Value, value string) (bool, error) {
+	if len(value) == 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	suffixes, ok := splitList(value, false)
+	if !ok {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return matchStrings(v, func(s string) bool {
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(s, suffix) {
+				return true
+			}
+		}
+		return false
+	}, fmt.Sprintf("String doesn't end with any of %q", value))
+}
+
+// validateUniqueFold requires the string keys of a map to stay distinct once lowercased, e.g. for a map
+// of HTTP headers holding both "Content-Type" and "content-type". The first colliding pair in sorted
+// order is reported.
+func validateUniqueFold(v reflect.Value, value string) (bool, error) {
+	if len(value) != 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return false, unsupportedType(v)
+	}
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	seen := make(map[string]string, len(keys))
+	for _, key := range keys {
+		folded := strings.ToLower(key)
+		if other, ok := seen[folded]; ok {
+			return false, ValidationError{Err: errors.Errorf("Keys %q and %q collide case-insensitively", other, key)}
+		}
+		seen[folded] = key
+	}
+	return true, nil
+}
+
+// validateNumLen requires a string of ASCII digits only, with either an exact length
+// ("numlen:11") or a length in an inclusive range ("numlen:9..11").
+func validateNumLen(v reflect.Value, value string) (bool, error) {
+	minValue, maxValue, isRange := strings.Cut(value, "..")
+	min, minErr := strconv.Atoi(minValue)
+	max, maxErr := min, error(nil)
+	if isRange {
+		max, maxErr = strconv.Atoi(maxValue)
+	}
+	if minErr != nil || maxErr != nil || min < 1 || min > max {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	digits := value
+	if isRange {
+		digits = minValue + " to " + maxValue
+	}
+	return matchStrings(v, func(s string) bool {
+		if len(s) < min || len(s) > max {
+			return false
+		}
+		for i := 0; i < len(s); i++ {
+			if s[i] < '0' || s[i] > '9' {
+				return false
+			}
+		}
+		return true
+	}, fmt.Sprintf("String is not a number of %s digits", digits))
+}
+
+// validateOnlyRunes requires a string made of the runes of its argument only, e.g. "onlyrunes:abcdef0123456789-".
+func validateOnlyRunes(v reflect.Value, value string) (bool, error) {
+	if len(value) == 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	allowed := make(map[rune]struct{}, len(value))
+	for _, r := range value {
+		allowed[r] = struct{}{}
+	}
+	return matchStrings(v, func(s string) bool {
+		for _, r := range s {
+			if _, ok := allowed[r]; !ok {
+				return false
+			}
+		}
+		return true
+	}, fmt.Sprintf("String has characters other than %q", value))
+}
+
+// validateRuneRange requires a string whose runes all have a code point in the inclusive range of its argument,
+// e.g. "runerange:0,127" for basic Latin only. The bounds may also be hexadecimal, as in "runerange:0x400,0x4FF".
+func validateRuneRange(v reflect.Value, value string) (bool, error) {
+	minValue, maxValue, ok := strings.Cut(value, ",")
+	min, minErr := strconv.ParseInt(minValue, 0, 32)
+	max, maxErr := strconv.ParseInt(maxValue, 0, 32)
+	if !ok || minErr != nil || maxErr != nil || min < 0 || min > max {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return matchStrings(v, func(s string) bool {
+		for _, r := range s {
+			if int64(r) < min || int64(r) > max {
+				return false
+			}
+		}
+		return true
+	}, fmt.Sprintf("String has characters outside of the range %s", value))
+}
+
+// validateCount requires a string with exactly n non-overlapping occurrences of a substring, given as
+// "count:substr,n", e.g. "count:,,3" for a line of 4 comma-separated values. The last comma of the
+// argument separates n, so the substring may contain commas.
+func validateCount(v reflect.Value, value string) (bool, error) {
+	i := strings.LastIndexByte(value, ',')
+	if i <= 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	substr := value[:i]
+	expected, err := strconv.Atoi(value[i+1:])
+	if err != nil || expected < 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		if n := strings.Count(v.String(), substr); n != expected {
+			return false, ValidationError{Err: errors.Errorf("String contains %q %d times, expected %d", substr, n, expected)}
+		}
+		return true, nil
+	case elemKind(v) == reflect.String:
+		for j := 0; j < v.Len(); j++ {
+			if n := strings.Count(v.Index(j).String(), substr); n != expected {
+				return false, elemError(v, j, errors.Errorf("The string on position %d contains %q %d times, expected %d", j, substr, n, expected))
+			}
+		}
+		return true, nil
+	default:
+		return false, unsupportedType(v)
+	}
+}
+
+// presenceRules are the validators checking whether a field is set, which see pointers
+// themselves instead of the values they point to.
+var presenceRules = map[string]struct{}{
+	"required":  {},
+	"isdefault": {},
+	"nonnil":    {},
+}
+
+// isZero reports whether v is the zero value of its type. A time.Time is zero if it is the zero instant,
+// whatever its location, as reported by its IsZero method, unless it is reached through an unexported
+// field, which only lets it be compared to the zero value.
+func isZero(v reflect.Value) bool {
+	if v.Type() == timeType && v.CanInterface() {
+		return v.Interface().(time.Time).IsZero()
+	}
+	return v.IsZero()
+}
+
+// validateRequired requires a non-zero value, e.g. a non-nil pointer or a non-empty string; an empty slice
+// or map is not set either. With "nonnil" a slice or map only has to be non-nil, so that an empty one can
+// tell "clear the list" from "leave it as is", and with "nonempty" it has to have elements, whether nil or not.
+func validateRequired(v reflect.Value, value string) (bool, error) {
+	switch value {
+	case "":
+		if isZero(v) || (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+			return false, ValidationError{Err: errors.New("Field is required")}
+		}
+	case "nonnil":
+		if !isNilable(v.Kind()) {
+			return false, unsupportedType(v)
+		}
+		if v.IsNil() {
+			return false, ValidationError{Err: errors.New("Field is required")}
+		}
+	case "nonempty":
+		switch v.Kind() {
+		case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		default:
+			return false, unsupportedType(v)
+		}
+		if v.Len() == 0 {
+			return false, ValidationError{Err: errors.New("Field must not be empty")}
+		}
+	default:
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	return true, nil
+}
+
+// isNilable reports whether the values of kind k can be nil.
+func isNilable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Pointer, reflect.Interface, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+// validateNonNil requires a non-nil chan, func, map, pointer, interface or slice, e.g. a callback that
+// is going to be called. Unlike required, it accepts a non-nil empty map or slice.
+func validateNonNil(v reflect.Value, value string) (bool, error) {
+	if len(value) != 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if !isNilable(v.Kind()) {
+		return false, unsupportedType(v)
+	}
+	if v.IsNil() {
+		return false, ValidationError{Err: errors.New("Field must not be nil")}
+	}
+	return true, nil
+}
+
+// validateIsDefault is the opposite of validateRequired, it requires the zero value, e.g. a nil pointer.
+func validateIsDefault(v reflect.Value, value string) (bool, error) {
+	if len(value) != 0 {
+		return false, ValidationError{Err: ErrInvalidValidatorSyntax}
+	}
+	if !isZero(v) {
+		return false, ValidationError{Err: errors.New("Field must not be set")}
+	}
+	return true, nil
 }
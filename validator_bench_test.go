@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type slowStruct struct {
+	F0 int `validate:"sleep:1"`
+	F1 int `validate:"sleep:1"`
+	F2 int `validate:"sleep:1"`
+	F3 int `validate:"sleep:1"`
+	F4 int `validate:"sleep:1"`
+	F5 int `validate:"sleep:1"`
+	F6 int `validate:"sleep:1"`
+	F7 int `validate:"sleep:1"`
+}
+
+func withSleepValidator(b *testing.B) {
+	RegisterValidator("sleep", func(v reflect.Value, value string) (bool, error) {
+		ms, _ := strconv.Atoi(value)
+		time.Sleep(time.Duration(ms) * time.Millisecond)
+		return true, nil
+	})
+	b.Cleanup(func() { unregisterValidator("sleep") })
+}
+
+func BenchmarkValidateSlow(b *testing.B) {
+	withSleepValidator(b)
+	for i := 0; i < b.N; i++ {
+		_ = Validate(slowStruct{})
+	}
+}
+
+func BenchmarkValidateParallelSlow(b *testing.B) {
+	withSleepValidator(b)
+	for i := 0; i < b.N; i++ {
+		_ = ValidateParallel(slowStruct{})
+	}
+}
+
+type stringSlices struct {
+	Names []string `validate:"min:1;max:16;in:alice,bob,carol"`
+	Codes []string `validate:"regexp:^[a-z]+$"`
+}
+
+type intSlices struct {
+	IDs   []int `validate:"min:1;max:1000;in:1,2,3,4,5"`
+	Ports []int `validate:"between:1,65535"`
+}
+
+const benchSliceLen = 100
+
+func BenchmarkValidateStringSlice(b *testing.B) {
+	names := []string{"alice", "bob", "carol"}
+	v := stringSlices{Names: make([]string, benchSliceLen), Codes: make([]string, benchSliceLen)}
+	for i := range v.Names {
+		v.Names[i] = names[i%len(names)]
+		v.Codes[i] = "abc"
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Validate(v)
+	}
+}
+
+func BenchmarkValidateIntSlice(b *testing.B) {
+	v := intSlices{IDs: make([]int, benchSliceLen), Ports: make([]int, benchSliceLen)}
+	for i := range v.IDs {
+		v.IDs[i] = i%5 + 1
+		v.Ports[i] = 8000 + i
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Validate(v)
+	}
+}
+
+type patterns struct {
+	Email string `validate:"regexp:^[a-z0-9._%+-]+@[a-z0-9.-]+\\.[a-z]{2,}$"`
+	Slug  string `validate:"iregexp:^[a-z0-9]+(-[a-z0-9]+)*$"`
+}
+
+func BenchmarkValidateRegexp(b *testing.B) {
+	v := patterns{Email: "jane.doe@example.com", Slug: "Tag-Validation"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Validate(v)
+	}
+}
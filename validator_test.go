@@ -1,8 +1,19 @@
 package validation
 
 import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -333,3 +344,2239 @@ func TestValidate(t *testing.T) {
 	}
 
 }
+
+func TestValidateOrder(t *testing.T) {
+	v := struct {
+		A string `validate:"len:1"`
+		B int    `validate:"min:5"`
+		C string `validate:"in:x"`
+		D int    `validate:"max:1"`
+	}{
+		A: "abc",
+		B: 1,
+		C: "y",
+		D: 2,
+	}
+	want := []string{
+		"lengths don't match",
+		"Integer is less than allowed",
+		"Field value isn't allowed",
+		"Integer is more than allowed",
+	}
+	for i := 0; i < 10; i++ {
+		err := Validate(v)
+		e := ValidationErrors{}
+		assert.True(t, errors.As(err, &e))
+		got := make([]string, 0, len(e))
+		for _, ve := range e {
+			got = append(got, ve.Error())
+		}
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestValidateParallel(t *testing.T) {
+	v := struct {
+		A string   `validate:"len:1"`
+		B int      `validate:"min:5"`
+		C string   `validate:"in:x"`
+		D int      `validate:"max:1"`
+		E []string `validate:"len:2"`
+		F string
+		g int
+		H string `validate:"len"`
+	}{
+		A: "abc",
+		B: 1,
+		C: "y",
+		D: 2,
+		E: []string{"ab", "cd"},
+	}
+	want, got := Validate(v), ValidateParallel(v)
+	assert.Len(t, got.(ValidationErrors), 5)
+	assert.Equal(t, want.Error(), got.Error())
+	assert.NoError(t, ValidateParallel(struct{}{}))
+	assert.ErrorIs(t, ValidateParallel(1), ErrNotStruct)
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{
+			name: "valid urls",
+			v: struct {
+				Any      string   `validate:"url:"`
+				Scheme   string   `validate:"url:https"`
+				Schemes  []string `validate:"url:http,https"`
+				Host     string   `validate:"url:host=example.com"`
+				HostPort string   `validate:"url:scheme=https,host=example.com:8443"`
+			}{
+				Any:      "ftp://files.example.com/a.txt",
+				Scheme:   "https://example.com",
+				Schemes:  []string{"http://a.com", "HTTPS://b.com"},
+				Host:     "http://EXAMPLE.com:8080/callback",
+				HostPort: "https://example.com:8443/",
+			},
+		},
+		{
+			name: "not a url",
+			v: struct {
+				F string `validate:"url:"`
+			}{F: "example.com/path"},
+			wantErr: "String is not a valid URL",
+		},
+		{
+			name: "wrong scheme",
+			v: struct {
+				F string `validate:"url:https"`
+			}{F: "http://example.com"},
+			wantErr: `URL scheme "http" is not allowed`,
+		},
+		{
+			name: "wrong host",
+			v: struct {
+				F string `validate:"url:scheme=https,host=example.com"`
+			}{F: "https://evil.com/example.com"},
+			wantErr: `URL host "evil.com" is not allowed`,
+		},
+		{
+			name: "wrong port",
+			v: struct {
+				F string `validate:"url:host=example.com:8443"`
+			}{F: "https://example.com:8080"},
+			wantErr: `URL host "example.com:8080" is not allowed`,
+		},
+		{
+			name: "wrong slice element",
+			v: struct {
+				F []string `validate:"url:host=example.com"`
+			}{F: []string{"https://example.com", "https://other.com"}},
+			wantErr: `The string on position 1 is not allowed: URL host "other.com" is not allowed`,
+		},
+		{
+			name: "unknown constraint",
+			v: struct {
+				F string `validate:"url:port=80"`
+			}{F: "https://example.com"},
+			wantErr: ErrInvalidValidatorSyntax.Error(),
+		},
+		{
+			name: "empty constraint",
+			v: struct {
+				F string `validate:"url:host="`
+			}{F: "https://example.com"},
+			wantErr: ErrInvalidValidatorSyntax.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMaxErrors(t *testing.T) {
+	v := struct {
+		A string `validate:"len:1"`
+		B int    `validate:"min:5"`
+		C string `validate:"in:x"`
+		D int    `validate:"max:1"`
+	}{
+		A: "abc",
+		B: 1,
+		C: "y",
+		D: 2,
+	}
+	for _, tt := range []struct {
+		maxErrors int
+		want      int
+	}{
+		{maxErrors: 0, want: 4},
+		{maxErrors: 1, want: 1},
+		{maxErrors: 3, want: 3},
+		{maxErrors: 10, want: 4},
+	} {
+		err := ValidateWithOptions(v, Options{MaxErrors: tt.maxErrors})
+		assert.Len(t, err.(ValidationErrors), tt.want)
+	}
+	err := ValidateWithOptions(v, Options{MaxErrors: 2})
+	assert.EqualError(t, err, "lengths don't matchInteger is less than allowed")
+}
+
+type account struct {
+	Email    string `validate:"method:HasEmail"`
+	Password string `validate:"method:CheckPassword"`
+}
+
+func (a account) HasEmail() bool {
+	return strings.Contains(a.Email, "@")
+}
+
+func (a account) CheckPassword() error {
+	if a.Password == a.Email {
+		return errors.New("Password must differ from the email")
+	}
+	return nil
+}
+
+func TestValidateMethod(t *testing.T) {
+	assert.NoError(t, Validate(account{Email: "bob@example.com", Password: "secret"}))
+
+	err := Validate(account{Email: "bob", Password: "bob"})
+	e := err.(ValidationErrors)
+	assert.Len(t, e, 2)
+	assert.Equal(t, "Field Email doesn't satisfy method HasEmail", e[0].Error())
+	assert.Equal(t, "Password must differ from the email", e[1].Error())
+	assert.Equal(t, "method", e[1].Rule)
+
+	assert.EqualError(t, Validate(struct {
+		A string `validate:"method:Missing"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateInRanges(t *testing.T) {
+	type ratings struct {
+		Score   int    `validate:"in:1-5,10,20-25"`
+		Offset  int    `validate:"in:-5--3,-1,0-2"`
+		History []int8 `validate:"in:1-5,10"`
+		Code    string `validate:"in:1-5"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "range bounds", v: ratings{Score: 1, Offset: -5, History: []int8{5, 10}, Code: "1-5"}},
+		{name: "singletons", v: ratings{Score: 10, Offset: -1, Code: "1-5"}},
+		{name: "inside ranges", v: ratings{Score: 22, Offset: -4, History: []int8{3}, Code: "1-5"}},
+		{name: "between ranges", v: ratings{Score: 6, Offset: 1, Code: "1-5"}, wantErr: "Field value isn't allowed"},
+		{name: "negative gap", v: ratings{Score: 1, Offset: -2, Code: "1-5"}, wantErr: "Field value isn't allowed"},
+		{name: "element", v: ratings{Score: 1, History: []int8{2, 11}, Code: "1-5"}, wantErr: "The integer on position 1 is less than allowed"},
+		{name: "string keeps dash", v: ratings{Score: 1, Code: "3"}, wantErr: "Field value isn't allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+	for _, set := range []string{"5-1", "1-", "-", "1-2-3", "a-b", "1,x"} {
+		err := ValidateSpec(ratings{Code: "1-5"}, map[string]string{"Score": "in:" + set})
+		assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error(), set)
+	}
+}
+
+func TestValidateInUint(t *testing.T) {
+	type levels struct {
+		Level  uint8  `validate:"in:1,2,3"`
+		Levels []uint `validate:"in:1,2,3"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "allowed", v: levels{Level: 3, Levels: []uint{1, 2, 3}}},
+		{name: "scalar", v: levels{Level: 4}, wantErr: "Field value isn't allowed"},
+		{name: "slice", v: levels{Level: 1, Levels: []uint{2, 5}}, wantErr: "The integer on position 1 is not allowed"},
+		{name: "negative token", v: struct {
+			U uint `validate:"in:-1,1"`
+		}{U: 1}, wantErr: ErrInvalidValidatorSyntax.Error()},
+		{name: "non-numeric token", v: struct {
+			U []uint32 `validate:"in:1,one"`
+		}{U: []uint32{1}}, wantErr: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateEmptyIn(t *testing.T) {
+	v := struct {
+		Role  string   `validate:"in:"`
+		Roles []string `validate:"in:"`
+		Level int      `validate:"in:1,2"`
+	}{Role: "admin", Roles: []string{"admin"}, Level: 3}
+
+	err := Validate(v)
+	assert.EqualError(t, err, "Field value isn't allowed"+"Field value isn't allowed"+"Field value isn't allowed")
+
+	err = ValidateWithOptions(v, Options{EmptyInIsSyntaxError: true})
+	assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error()+ErrInvalidValidatorSyntax.Error()+"Field value isn't allowed")
+	assert.Equal(t, "in", err.(ValidationErrors)[0].Rule)
+}
+
+func TestValidateLenField(t *testing.T) {
+	type post struct {
+		Body    string
+		Summary string `validate:"maxlenfield:Body"`
+		Title   string `validate:"minlenfield:Tag"`
+		Tag     string
+	}
+	assert.NoError(t, Validate(post{Body: "long body", Summary: "short", Title: "title", Tag: "tag"}))
+	assert.NoError(t, Validate(post{Body: "same", Summary: "same", Title: "ab", Tag: "cd"}))
+	assert.EqualError(t, Validate(post{Body: "short", Summary: "long summary", Title: "title"}),
+		"Field Summary is longer than field Body")
+	assert.EqualError(t, Validate(post{Title: "a", Tag: "tag"}),
+		"Field Title is shorter than field Tag")
+
+	err := Validate(struct {
+		Count   int
+		Summary string `validate:"maxlenfield:Count"`
+		Missing string `validate:"maxlenfield:Nope"`
+		Number  int    `validate:"minlenfield:Summary"`
+	}{})
+	assert.EqualError(t, err, ErrInvalidValidatorSyntax.Error()+ErrInvalidValidatorSyntax.Error()+"Field of type int: unsupported field type")
+}
+
+func TestValidateWithTagKey(t *testing.T) {
+	v := struct {
+		Name  string `binding:"len:3" validate:"len:5"`
+		Level int    `rules:"min:1"`
+	}{
+		Name: "abc",
+	}
+	assert.NoError(t, ValidateWithTagKey(v, "binding"))
+	assert.EqualError(t, ValidateWithTagKey(v, "rules"), "Integer is less than allowed")
+	assert.EqualError(t, Validate(v), "lengths don't match")
+	assert.EqualError(t, ValidateWithTagKey(v, ""), "lengths don't match")
+}
+
+type Color string
+
+type Level int8
+
+func TestValidateNamedTypes(t *testing.T) {
+	valid := struct {
+		C        Color   `validate:"in:red,green,blue"`
+		Cs       []Color `validate:"in:red,green,blue"`
+		CLen     Color   `validate:"len:5"`
+		CsLen    []Color `validate:"len:4"`
+		CMin     Color   `validate:"min:3"`
+		CMax     Color   `validate:"max:3"`
+		CBetween Color   `validate:"between:1,5"`
+		CURL     Color   `validate:"url:https"`
+		L        Level   `validate:"in:1,2,3"`
+		Ls       []Level `validate:"in:1,2,3"`
+		LMin     Level   `validate:"min:1"`
+		LsMin    []Level `validate:"min:1"`
+		LMax     Level   `validate:"max:5"`
+		LsMax    []Level `validate:"max:5"`
+		LBetween Level   `validate:"between:1,5"`
+		Int64    int64   `validate:"between:1,5"`
+		Int32s   []int32 `validate:"between:1,5"`
+	}{
+		C:        "green",
+		Cs:       []Color{"red", "blue"},
+		CLen:     "green",
+		CsLen:    []Color{"blue", "pink"},
+		CMin:     "red",
+		CMax:     "red",
+		CBetween: "blue",
+		CURL:     "https://example.com",
+		L:        2,
+		Ls:       []Level{1, 3},
+		LMin:     1,
+		LsMin:    []Level{1, 2},
+		LMax:     5,
+		LsMax:    []Level{4, 5},
+		LBetween: 3,
+		Int64:    5,
+		Int32s:   []int32{1, 5},
+	}
+	assert.NoError(t, Validate(valid))
+
+	invalid := struct {
+		C      Color   `validate:"in:red,green,blue"`
+		Cs     []Color `validate:"in:red,green,blue"`
+		CLen   Color   `validate:"len:5"`
+		L      Level   `validate:"in:1,2,3"`
+		LMin   Level   `validate:"min:1"`
+		LsMax  []Level `validate:"max:5"`
+		Int32s []int32 `validate:"between:1,5"`
+	}{
+		C:      "pink",
+		Cs:     []Color{"red", "pink"},
+		CLen:   "red",
+		L:      4,
+		LMin:   0,
+		LsMax:  []Level{4, 6},
+		Int32s: []int32{0},
+	}
+	err := Validate(invalid)
+	assert.Len(t, err.(ValidationErrors), 7)
+}
+
+func TestValidateNotBlank(t *testing.T) {
+	type form struct {
+		Title string   `validate:"notblank:"`
+		Tags  []string `validate:"notblank:"`
+	}
+	assert.NoError(t, Validate(form{Title: " a ", Tags: []string{"x", "\ty"}}))
+	assert.NoError(t, Validate(form{Title: "a"}))
+	assert.EqualError(t, Validate(form{Title: ""}), "String is blank")
+	assert.EqualError(t, Validate(form{Title: " \t\n"}), "String is blank")
+	assert.EqualError(t, Validate(form{Title: "a", Tags: []string{"x", "   "}}), "The string on position 1 is blank")
+	assert.EqualError(t, Validate(struct {
+		Title string `validate:"notblank:yes"`
+		Count int    `validate:"notblank:"`
+	}{Title: "a"}), ErrInvalidValidatorSyntax.Error()+"Field of type int: unsupported field type")
+}
+
+func TestValidateDigits(t *testing.T) {
+	valid := struct {
+		Pin      int      `validate:"digits:4"`
+		Negative int      `validate:"digits:3"`
+		Zero     int      `validate:"digits:1"`
+		Uint     uint16   `validate:"digits:5"`
+		MinInt   int64    `validate:"digits:19"`
+		Slice    []uint   `validate:"digits:2"`
+		Between  int      `validate:"digitsbetween:2,4"`
+		BetweenN int8     `validate:"digitsbetween:3,3"`
+		BetweenS []int    `validate:"digitsbetween:1,2"`
+		MaxUint  uint64   `validate:"digitsbetween:20,20"`
+		Named    Level    `validate:"digits:2"`
+		Empty    []uint32 `validate:"digits:7"`
+	}{
+		Pin:      1234,
+		Negative: -100,
+		Zero:     0,
+		Uint:     65535,
+		MinInt:   -9223372036854775808,
+		Slice:    []uint{10, 99},
+		Between:  -999,
+		BetweenN: -128,
+		BetweenS: []int{0, -12, 9},
+		MaxUint:  18446744073709551615,
+		Named:    42,
+	}
+	assert.NoError(t, Validate(valid))
+
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{
+			name: "short pin",
+			v: struct {
+				Pin int `validate:"digits:4"`
+			}{Pin: 123},
+			wantErr: "Integer has 3 digits, expected 4",
+		},
+		{
+			name: "negative sign is not a digit",
+			v: struct {
+				Pin int `validate:"digits:4"`
+			}{Pin: -123},
+			wantErr: "Integer has 3 digits, expected 4",
+		},
+		{
+			name: "zero",
+			v: struct {
+				Pin int `validate:"digits:4"`
+			}{},
+			wantErr: "Integer has 1 digits, expected 4",
+		},
+		{
+			name: "slice",
+			v: struct {
+				Pins []int `validate:"digits:2"`
+			}{Pins: []int{10, 100}},
+			wantErr: "The integer on position 1 is not allowed: Integer has 3 digits, expected 2",
+		},
+		{
+			name: "between",
+			v: struct {
+				N uint `validate:"digitsbetween:2,3"`
+			}{N: 1000},
+			wantErr: "Integer has 4 digits, expected between 2 and 3",
+		},
+		{
+			name: "bad spec",
+			v: struct {
+				A int    `validate:"digits:x"`
+				B int    `validate:"digits:0"`
+				C int    `validate:"digitsbetween:3"`
+				D int    `validate:"digitsbetween:3,1"`
+				E string `validate:"digits:3"`
+			}{},
+			wantErr: strings.Repeat(ErrInvalidValidatorSyntax.Error(), 4) + "Field of type string: unsupported field type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, Validate(tt.v), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateInSpacedTokens(t *testing.T) {
+	type spaced struct {
+		Size   int      `validate:"in:5, 7"`
+		Sizes  []int    `validate:"in:1-3, 9"`
+		Color  string   `validate:"in:red, green , blue"`
+		Colors []string `validate:"in: red,green"`
+		Level  uint     `validate:"in:1, 2"`
+		Flag   bool     `validate:"in: true"`
+	}
+	valid := spaced{Size: 7, Sizes: []int{2, 9}, Color: "green", Colors: []string{"red"}, Level: 2, Flag: true}
+	assert.NoError(t, Validate(valid))
+
+	invalid := valid
+	invalid.Size, invalid.Color = 6, " blue"
+	assert.EqualError(t, Validate(invalid), "Field value isn't allowed"+"Field value isn't allowed")
+}
+
+func TestValidateInNegated(t *testing.T) {
+	type user struct {
+		Name   string   `validate:"in:!admin,root"`
+		Groups []string `validate:"in:!wheel"`
+		UID    int      `validate:"in:!0-99"`
+		GIDs   []uint   `validate:"in:!0"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "allowed", v: user{Name: "bob", Groups: []string{"staff"}, UID: 1000, GIDs: []uint{100}}},
+		{name: "name", v: user{Name: "root", UID: 1000}, wantErr: "Field value isn't allowed"},
+		{name: "group", v: user{Name: "bob", Groups: []string{"staff", "wheel"}, UID: 1000},
+			wantErr: "The string on position 1 is not allowed"},
+		{name: "uid range", v: user{Name: "bob", UID: 42}, wantErr: "Field value isn't allowed"},
+		{name: "gid", v: user{Name: "bob", UID: 1000, GIDs: []uint{0}}, wantErr: "The integer on position 0 is not allowed"},
+		{name: "no tokens", v: struct {
+			Name string `validate:"in:!"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+		{name: "malformed tokens", v: struct {
+			UID int `validate:"in:!x"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateIncludeValue(t *testing.T) {
+	v := struct {
+		Name   string   `validate:"len:2"`
+		Age    int      `validate:"min:18"`
+		Tags   []string `validate:"in:a,b"`
+		Scores []int    `validate:"max:10"`
+		Bad    string   `validate:"len:x"`
+		Fine   string   `validate:"len:1"`
+	}{
+		Name:   "abc",
+		Age:    7,
+		Tags:   []string{"a", "c"},
+		Scores: []int{20},
+		Fine:   "x",
+	}
+	err := ValidateWithOptions(v, Options{IncludeValue: true})
+	e := ValidationErrors{}
+	assert.True(t, errors.As(err, &e))
+	got := make([]string, 0, len(e))
+	for _, ve := range e {
+		got = append(got, ve.Error())
+	}
+	assert.Equal(t, []string{
+		"lengths don't match (value: abc)",
+		"Integer is less than allowed (value: 7)",
+		"The string on position 1 is not allowed (index 1: c)",
+		"The integer on position 0 is more than allowed (index 0: 20)",
+		ErrInvalidValidatorSyntax.Error(),
+	}, got)
+	assert.ErrorIs(t, e[4].Err, ErrInvalidValidatorSyntax)
+
+	assert.EqualError(t, Validate(v), "lengths don't match"+
+		"Integer is less than allowed"+
+		"The string on position 1 is not allowed"+
+		"The integer on position 0 is more than allowed"+
+		ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateTranslator(t *testing.T) {
+	catalog := map[string]string{
+		"min":      "Wert zu klein",
+		"len.Code": "Code muss %s Zeichen haben",
+	}
+	translate := func(rule, field string) string {
+		if msg, ok := catalog[rule+"."+field]; ok {
+			return strings.ReplaceAll(msg, "%s", "2")
+		}
+		return catalog[rule]
+	}
+	v := struct {
+		Age  int      `validate:"min:18"`
+		Code string   `validate:"len:2"`
+		Name string   `validate:"len:1"`
+		Tags []string `validate:"dive;min:2"`
+		Bad  string   `validate:"len:x"`
+	}{Age: 7, Code: "abc", Name: "ab", Tags: []string{"a"}}
+	err := ValidateWithOptions(v, Options{Translator: translate})
+	e := ValidationErrors{}
+	assert.True(t, errors.As(err, &e))
+	got := make([]string, 0, len(e))
+	for _, ve := range e {
+		got = append(got, ve.Error())
+	}
+	assert.Equal(t, []string{
+		"Wert zu klein",
+		"Code muss 2 Zeichen haben",
+		"lengths don't match",
+		"[0]: Wert zu klein",
+		ErrInvalidValidatorSyntax.Error(),
+	}, got)
+	assert.Equal(t, "min", e[0].Rule)
+
+	// English by default
+	assert.EqualError(t, ValidateWithOptions(struct {
+		Age int `validate:"min:18"`
+	}{Age: 7}, Options{}), "Integer is less than allowed")
+}
+
+func TestValidateISBN(t *testing.T) {
+	assert.NoError(t, Validate(struct {
+		Any     string   `validate:"isbn:"`
+		AnyToo  string   `validate:"isbn:"`
+		Ten     string   `validate:"isbn:10"`
+		TenX    string   `validate:"isbn:10"`
+		Thirt   string   `validate:"isbn:13"`
+		Shelves []string `validate:"isbn:"`
+	}{
+		Any:     "978-0-306-40615-7",
+		AnyToo:  "0306406152",
+		Ten:     "0 306 40615 2",
+		TenX:    "0-8044-2957-X",
+		Thirt:   "9780306406157",
+		Shelves: []string{"0306406152", "978 0 306 40615 7"},
+	}))
+
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{
+			name: "bad checksum",
+			v: struct {
+				F string `validate:"isbn:"`
+			}{F: "978-0-306-40615-8"},
+			wantErr: "String is not a valid ISBN",
+		},
+		{
+			name: "isbn-10 given for 13",
+			v: struct {
+				F string `validate:"isbn:13"`
+			}{F: "0306406152"},
+			wantErr: "String is not a valid ISBN-13",
+		},
+		{
+			name: "isbn-13 given for 10",
+			v: struct {
+				F string `validate:"isbn:10"`
+			}{F: "9780306406157"},
+			wantErr: "String is not a valid ISBN-10",
+		},
+		{
+			name: "X not in last position",
+			v: struct {
+				F string `validate:"isbn:10"`
+			}{F: "X306406152"},
+			wantErr: "String is not a valid ISBN-10",
+		},
+		{
+			name: "slice",
+			v: struct {
+				F []string `validate:"isbn:"`
+			}{F: []string{"0306406152", "abc"}},
+			wantErr: "The string on position 1 is not a valid ISBN",
+		},
+		{
+			name: "bad spec",
+			v: struct {
+				F string `validate:"isbn:11"`
+				G int    `validate:"isbn:"`
+			}{},
+			wantErr: ErrInvalidValidatorSyntax.Error() + "Field of type int: unsupported field type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, Validate(tt.v), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateUnsafe(t *testing.T) {
+	type secret struct {
+		name  string   `validate:"len:5"`
+		level int      `validate:"min:3"`
+		tags  []string `validate:"in:a,b"`
+		Code  string   `validate:"len:2"`
+		note  string
+	}
+	assert.NoError(t, ValidateUnsafe(secret{name: "alice", level: 3, tags: []string{"a"}, Code: "ab"}))
+	assert.EqualError(t, ValidateUnsafe(secret{name: "bob", level: 3, Code: "ab"}), "lengths don't match")
+	err := ValidateUnsafe(&secret{})
+	assert.ErrorIs(t, err, ErrNotStruct)
+
+	err = ValidateUnsafe(secret{name: "alice", level: 1, tags: []string{"c"}, Code: "abc"})
+	assert.Len(t, err.(ValidationErrors), 3)
+
+	err = Validate(secret{name: "alice", level: 3, Code: "ab"})
+	assert.EqualError(t, err, strings.Repeat(ErrValidateForUnexportedFields.Error(), 3))
+}
+
+func TestValidateCountry(t *testing.T) {
+	assert.NoError(t, Validate(struct {
+		Alpha2  string   `validate:"country:alpha2"`
+		Alpha3  string   `validate:"country:alpha3"`
+		Visited []string `validate:"country:alpha2"`
+	}{
+		Alpha2:  "DE",
+		Alpha3:  "USA",
+		Visited: []string{"FR", "JP", "AX"},
+	}))
+	assert.EqualError(t, Validate(struct {
+		F string `validate:"country:alpha2"`
+	}{F: "XX"}), `"XX" is not a valid ISO 3166-1 alpha-2 country code`)
+	assert.EqualError(t, Validate(struct {
+		F string `validate:"country:alpha2"`
+	}{F: "DEU"}), `"DEU" is not a valid ISO 3166-1 alpha-2 country code`)
+	assert.EqualError(t, Validate(struct {
+		F string `validate:"country:alpha3"`
+	}{F: "usa"}), `"usa" is not a valid ISO 3166-1 alpha-3 country code`)
+	assert.EqualError(t, Validate(struct {
+		F []string `validate:"country:alpha3"`
+	}{F: []string{"GBR", "UK"}}), `The string on position 1 is not a valid ISO 3166-1 alpha-3 country code: "UK"`)
+	assert.EqualError(t, Validate(struct {
+		F string `validate:"country:"`
+		G int    `validate:"country:alpha2"`
+	}{}), ErrInvalidValidatorSyntax.Error()+"Field of type int: unsupported field type")
+	assert.Len(t, countryCodes, 249)
+	assert.Len(t, countryAlpha3Codes, 249)
+}
+
+func TestValidateCurrency(t *testing.T) {
+	type payment struct {
+		Currency string   `validate:"currency:"`
+		Accepted []string `validate:"currency:"`
+	}
+	assert.NoError(t, Validate(payment{Currency: "USD", Accepted: []string{"EUR", "JPY", "CHF"}}))
+	assert.EqualError(t, Validate(payment{Currency: "usd"}), `"usd" is not a valid ISO 4217 currency code`)
+	assert.EqualError(t, Validate(payment{Currency: "ABC"}), `"ABC" is not a valid ISO 4217 currency code`)
+	assert.EqualError(t, Validate(payment{Currency: "EUR", Accepted: []string{"GBP", "BTC"}}),
+		`The string on position 1 is not a valid ISO 4217 currency code: "BTC"`)
+	assert.EqualError(t, Validate(struct {
+		F string `validate:"currency:USD"`
+		G int    `validate:"currency:"`
+	}{}), ErrInvalidValidatorSyntax.Error()+"Field of type int: unsupported field type")
+}
+
+type version struct {
+	major, minor int
+}
+
+func (v version) MarshalText() ([]byte, error) {
+	if v.major < 0 {
+		return nil, errors.New("negative major version")
+	}
+	return []byte(strconv.Itoa(v.major) + "." + strconv.Itoa(v.minor)), nil
+}
+
+type shouting string
+
+func (s *shouting) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(*s))), nil
+}
+
+func TestValidateTextMarshaler(t *testing.T) {
+	type release struct {
+		Version version  `validate:"in:1.0,1.1,2.0"`
+		Short   version  `validate:"len:3"`
+		Name    shouting `validate:"in:ALPHA,BETA"`
+		Builds  int      `validate:"min:1"`
+	}
+	assert.NoError(t, Validate(release{Version: version{1, 1}, Short: version{2, 0}, Name: "beta", Builds: 1}))
+	assert.EqualError(t, Validate(release{Version: version{1, 2}, Short: version{10, 0}, Name: "gamma", Builds: 1}),
+		"Field value isn't allowed"+"lengths don't match"+"Field value isn't allowed")
+	assert.EqualError(t, Validate(release{Version: version{-1, 0}, Short: version{2, 0}, Name: "beta", Builds: 1}),
+		"Field can't be marshaled to text: negative major version")
+	assert.EqualError(t, Validate(struct {
+		Version version `validate:"min:1"`
+	}{}), "Field of type validation.version: unsupported field type")
+	assert.EqualError(t, Validate(struct {
+		S encoding.TextMarshaler `validate:"len:3"`
+	}{}), "Field of type encoding.TextMarshaler: unsupported field type")
+	assert.NoError(t, Validate(struct {
+		S encoding.TextMarshaler `validate:"len:3"`
+	}{S: version{1, 0}}))
+}
+
+type status int
+
+func (s status) String() string {
+	switch s {
+	case 1:
+		return "Active"
+	case 2:
+		return "Inactive"
+	default:
+		return "Unknown"
+	}
+}
+
+func TestValidateStringer(t *testing.T) {
+	type member struct {
+		Status status `validate:"in:Active,Inactive"`
+		Code   status `validate:"regexp:^[A-Z][a-z]+$;len:6"`
+		Level  status `validate:"min:1"`
+	}
+	assert.NoError(t, Validate(member{Status: 2, Code: 1, Level: 1}))
+	assert.EqualError(t, Validate(member{Status: 3, Code: 2, Level: 0}),
+		"Field value isn't allowed"+"lengths don't match"+"Integer is less than allowed")
+
+	// the integer form is checked whenever the rule can check it, else the String form
+	type roster struct {
+		Status  status   `validate:"in:0,1"`
+		Named   status   `validate:"in:!Unknown"`
+		History []status `validate:"in:0,1"`
+		Names   []status `validate:"in:Active,Inactive"`
+	}
+	assert.NoError(t, Validate(roster{Status: 0, Named: 1, History: []status{0, 1}, Names: []status{1, 2}}))
+	assert.EqualError(t, Validate(roster{Status: 2, Named: 3, History: []status{1, 2}, Names: []status{2, 0}}),
+		"Field value isn't allowed"+"Field value isn't allowed"+"The integer on position 1 is less than allowed"+
+			"The string on position 1 is not allowed")
+
+	assert.EqualError(t, Validate(struct {
+		S fmt.Stringer `validate:"len:3"`
+	}{}), "Field of type fmt.Stringer: unsupported field type")
+	assert.NoError(t, Validate(struct {
+		S fmt.Stringer `validate:"len:6"`
+	}{S: status(1)}))
+}
+
+func TestValidateMapMinMax(t *testing.T) {
+	type request struct {
+		Headers map[string]string `validate:"max:2"`
+		Params  map[string]int    `validate:"min:1"`
+	}
+	assert.NoError(t, Validate(request{
+		Headers: map[string]string{"a": "1", "b": "2"},
+		Params:  map[string]int{"page": 1},
+	}))
+	assert.NoError(t, Validate(request{Params: map[string]int{"page": 1}}))
+	assert.EqualError(t, Validate(request{
+		Headers: map[string]string{"a": "1", "b": "2", "c": "3"},
+	}), "Map has more entries than allowed"+"Map has fewer entries than allowed")
+}
+
+func TestValidateLenMinMax(t *testing.T) {
+	type order struct {
+		Items  []int          `validate:"lenmin:1;lenmax:3"`
+		Codes  [2]string      `validate:"lenmax:2"`
+		Meta   map[string]int `validate:"lenmax:1"`
+		Note   string         `validate:"lenmin:2"`
+		Scores []int          `validate:"lenmax:2;max:10"`
+	}
+	assert.NoError(t, Validate(order{Items: []int{100, 200}, Meta: map[string]int{"a": 1}, Note: "ok", Scores: []int{1, 2}}))
+
+	// min and max check the elements, lenmin and lenmax the slice itself
+	assert.EqualError(t, Validate(struct {
+		Items []int `validate:"lenmin:2;min:5"`
+	}{Items: []int{7}}), "Length 1 is less than allowed 2")
+
+	err := Validate(order{
+		Items:  []int{1, 2, 3, 4},
+		Meta:   map[string]int{"a": 1, "b": 2},
+		Note:   "x",
+		Scores: []int{1, 20, 3},
+	})
+	assert.EqualError(t, err, "Length 4 is more than allowed 3"+
+		"Length 2 is more than allowed 1"+
+		"Length 1 is less than allowed 2"+
+		"Length 3 is more than allowed 2"+
+		"The integer on position 1 is more than allowed")
+	assert.EqualError(t, Validate(order{Note: "ok"}), "Length 0 is less than allowed 1")
+
+	assert.EqualError(t, Validate(struct {
+		A int    `validate:"lenmin:1"`
+		B string `validate:"lenmax:x"`
+	}{}), "Field of type int: unsupported field type"+ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateBytes(t *testing.T) {
+	type upload struct {
+		Raw    []byte `validate:"max:8"`
+		Magic  []byte `validate:"len:4;regexp:^%PDF$"`
+		Kind   []byte `validate:"in:pdf,png"`
+		Digest []byte `validate:"between:2,4;lenmax:4"`
+	}
+	assert.NoError(t, Validate(upload{Raw: []byte("12345678"), Magic: []byte("%PDF"), Kind: []byte("png"), Digest: []byte{0xff, 0x00}}))
+	assert.EqualError(t, Validate(upload{Raw: make([]byte, 9), Magic: []byte("%PNG"), Kind: []byte("gif"), Digest: []byte{0xff}}),
+		"String length is more than allowed"+`String doesn't match "^%PDF$"`+"Field value isn't allowed"+"String length is not allowed")
+
+	// integer tokens check the bytes one by one, as for the other integer slices
+	type flags struct {
+		Bytes []uint8  `validate:"in:1,2"`
+		Words []uint16 `validate:"in:1,2"`
+		Not   []byte   `validate:"in:!0"`
+	}
+	assert.NoError(t, Validate(flags{Bytes: []uint8{1, 2}, Words: []uint16{1, 2}, Not: []byte{1}}))
+	assert.EqualError(t, Validate(flags{Bytes: []uint8{1, 3}, Words: []uint16{3}, Not: []byte{1, 0}}),
+		"The integer on position 1 is not allowed"+"The integer on position 0 is not allowed"+"The integer on position 1 is not allowed")
+}
+
+func TestValidateCapMinMax(t *testing.T) {
+	type pool struct {
+		Buf     []byte `validate:"capmax:4096;lenmax:16"`
+		Scratch []int  `validate:"capmin:8"`
+	}
+	assert.NoError(t, Validate(pool{Buf: make([]byte, 16, 4096), Scratch: make([]int, 0, 8)}))
+
+	// the capacity is checked even when the length is within bounds
+	assert.EqualError(t, Validate(pool{Buf: make([]byte, 4, 8192), Scratch: make([]int, 8, 8)}),
+		"Capacity 8192 is more than allowed 4096")
+	assert.EqualError(t, Validate(pool{Buf: nil, Scratch: make([]int, 7)}), "Capacity 7 is less than allowed 8")
+
+	assert.EqualError(t, Validate(struct {
+		A string `validate:"capmax:1"`
+		B [2]int `validate:"capmin:1"`
+		C []int  `validate:"capmax:x"`
+	}{}), "Field of type string: unsupported field type"+"Field of type [2]int: unsupported field type"+ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateRuleName(t *testing.T) {
+	v := struct {
+		Name    string `validate:"min:3;in:alice,bob"`
+		Age     int    `validate:"max:120"`
+		Bad     string `validate:"len"`
+		Unknown string `validate:"nope:1"`
+	}{
+		Name: "al",
+		Age:  200,
+	}
+	err := Validate(v)
+	e := ValidationErrors{}
+	assert.True(t, errors.As(err, &e))
+	rules := make([]string, 0, len(e))
+	for _, ve := range e {
+		rules = append(rules, ve.Rule)
+	}
+	assert.Equal(t, []string{"min", "in", "max", "", "nope"}, rules)
+	assert.Equal(t, "String length is less than allowed", e[0].Error())
+	assert.Equal(t, "Field value isn't allowed", e[1].Error())
+}
+
+func TestValidateBigInt(t *testing.T) {
+	parse := func(s string) *big.Int {
+		n, _ := new(big.Int).SetString(s, 10)
+		return n
+	}
+	type transfer struct {
+		Amount *big.Int `validate:"min:1000000000000000000"`
+		Limit  *big.Int `validate:"max:340282366920938463463374607431768211455"`
+		Fee    *big.Int `validate:"between:-1,100000000000000000000000"`
+	}
+	assert.NoError(t, Validate(transfer{}))
+	assert.NoError(t, Validate(transfer{
+		Amount: parse("1000000000000000000"),
+		Limit:  parse("340282366920938463463374607431768211455"),
+		Fee:    parse("-1"),
+	}))
+	assert.EqualError(t, Validate(transfer{
+		Amount: parse("999999999999999999"),
+		Limit:  parse("340282366920938463463374607431768211456"),
+		Fee:    parse("100000000000000000000001"),
+	}), "Integer is less than allowed"+"Integer is more than allowed"+"Integer is more than allowed")
+	assert.EqualError(t, Validate(transfer{Fee: parse("-2")}), "Integer is less than allowed")
+
+	assert.EqualError(t, Validate(struct {
+		A *big.Int `validate:"min:1e18"`
+		B *big.Int `validate:"max:"`
+		C *big.Int `validate:"between:1"`
+		D *big.Int `validate:"between:1,x"`
+	}{}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 4))
+}
+
+func TestValidateBigFloat(t *testing.T) {
+	parse := func(s string, prec uint) *big.Float {
+		f, _, _ := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+		return f
+	}
+	type quote struct {
+		Rate   *big.Float `validate:"between:0,1"`
+		Price  *big.Float `validate:"min:0.01"`
+		Volume *big.Float `validate:"max:1e30"`
+	}
+	assert.NoError(t, Validate(quote{}))
+	assert.NoError(t, Validate(quote{Rate: parse("1", 64), Price: big.NewFloat(0.01), Volume: parse("1e30", 200)}))
+	assert.NoError(t, Validate(quote{Price: parse("0.01", 300)}))
+	assert.EqualError(t, Validate(quote{
+		Rate:   parse("1.0000000001", 64),
+		Price:  parse("0.00999", 64),
+		Volume: parse("1000000000000000000000000000001", 200),
+	}), "Number is more than allowed"+"Number is less than allowed"+"Number is more than allowed")
+	assert.EqualError(t, Validate(quote{Rate: parse("-0.5", 64)}), "Number is less than allowed")
+
+	assert.EqualError(t, Validate(struct {
+		A *big.Float `validate:"min:x"`
+		B *big.Float `validate:"max:"`
+		C *big.Float `validate:"between:1"`
+		D *big.Float `validate:"between:1,0"`
+	}{}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 4))
+}
+
+func TestValidateApprox(t *testing.T) {
+	type measurement struct {
+		Pi      float64   `validate:"eqapprox:3.14,0.01"`
+		Ratio   float32   `validate:"neapprox:0,0.001"`
+		Samples []float64 `validate:"eqapprox:1,0.5"`
+	}
+	assert.NoError(t, Validate(measurement{Pi: 3.141592, Ratio: 0.5, Samples: []float64{0.5, 1, 1.5}}))
+	assert.NoError(t, Validate(measurement{Pi: 3.1499, Ratio: -0.0011}))
+	assert.EqualError(t, Validate(measurement{Pi: 3.151, Ratio: 0.0009}),
+		"Float is not approximately equal to 3.14"+"Float is approximately equal to 0")
+	assert.EqualError(t, Validate(measurement{Pi: 3.14, Ratio: 1, Samples: []float64{1, 1.51}}),
+		"The float on position 1 is not allowed: Float is not approximately equal to 1")
+	assert.NoError(t, Validate(struct {
+		Exact float64 `validate:"eqapprox:0.25,0"`
+	}{Exact: 0.25}))
+
+	assert.EqualError(t, Validate(struct {
+		A float64 `validate:"eqapprox:3.14"`
+		B float64 `validate:"eqapprox:x,0.1"`
+		C float64 `validate:"neapprox:1,-0.1"`
+		D int     `validate:"eqapprox:1,0.1"`
+	}{}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 3)+"Field of type int: unsupported field type")
+}
+
+func TestValidateTime(t *testing.T) {
+	type event struct {
+		Start  time.Time `validate:"between:2020-01-01,2030-01-01"`
+		End    time.Time `validate:"between:2020-01-01T00:00:00Z,2030-01-01,exclusive"`
+		Signup time.Time `validate:"min:2021-06-01T12:00:00+02:00"`
+		Expiry time.Time `validate:"max:2025-12-31"`
+	}
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.RFC3339, s)
+		assert.NoError(t, err)
+		return d
+	}
+	valid := event{
+		Start:  date("2020-01-01T00:00:00Z"),
+		End:    date("2029-12-31T23:59:59Z"),
+		Signup: date("2021-06-01T10:00:00Z"),
+		Expiry: date("2025-12-31T00:00:00Z"),
+	}
+	tests := []struct {
+		name    string
+		edit    func(*event)
+		wantErr string
+	}{
+		{name: "valid"},
+		{name: "inclusive upper bound", edit: func(e *event) { e.Start = date("2030-01-01T00:00:00Z") }},
+		{name: "other time zone", edit: func(e *event) { e.Start = date("2030-01-01T01:00:00+01:00") }},
+		{name: "before", edit: func(e *event) { e.Start = date("2019-12-31T23:59:59Z") },
+			wantErr: "Time 2019-12-31T23:59:59Z is before allowed 2020-01-01T00:00:00Z"},
+		{name: "after in other time zone", edit: func(e *event) { e.Start = date("2030-01-01T00:00:00-01:00") },
+			wantErr: "Time 2030-01-01T01:00:00Z is after allowed 2030-01-01T00:00:00Z"},
+		{name: "exclusive lower bound", edit: func(e *event) { e.End = date("2020-01-01T00:00:00Z") },
+			wantErr: "Time 2020-01-01T00:00:00Z is before allowed 2020-01-01T00:00:00Z"},
+		{name: "exclusive upper bound", edit: func(e *event) { e.End = date("2030-01-01T00:00:00Z") },
+			wantErr: "Time 2030-01-01T00:00:00Z is after allowed 2030-01-01T00:00:00Z"},
+		{name: "min", edit: func(e *event) { e.Signup = date("2021-06-01T09:59:59Z") },
+			wantErr: "Time 2021-06-01T09:59:59Z is before allowed 2021-06-01T10:00:00Z"},
+		{name: "max", edit: func(e *event) { e.Expiry = date("2025-12-31T00:00:01Z") },
+			wantErr: "Time 2025-12-31T00:00:01Z is after allowed 2025-12-31T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := valid
+			if tt.edit != nil {
+				tt.edit(&e)
+			}
+			err := Validate(e)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+	assert.EqualError(t, Validate(struct {
+		A time.Time `validate:"min:yesterday"`
+		B time.Time `validate:"between:2020-01-01"`
+		C time.Time `validate:"between:2020-01-01,2030-01-01,open"`
+	}{}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 3))
+}
+
+func TestValidateDuration(t *testing.T) {
+	type config struct {
+		Timeout time.Duration `validate:"max:30s"`
+		Backoff time.Duration `validate:"min:100ms"`
+		Poll    time.Duration `validate:"between:1s,1m"`
+	}
+	assert.NoError(t, Validate(config{Timeout: 30 * time.Second, Backoff: 100 * time.Millisecond, Poll: time.Second}))
+	assert.NoError(t, Validate(config{Backoff: time.Hour, Poll: time.Minute}))
+	assert.EqualError(t, Validate(config{Timeout: 31 * time.Second, Backoff: 99 * time.Millisecond, Poll: 61 * time.Second}),
+		"Duration 31s is more than allowed 30s"+
+			"Duration 99ms is less than allowed 100ms"+
+			"Duration 1m1s is more than allowed 1m0s")
+	assert.EqualError(t, Validate(config{Backoff: time.Second, Poll: 999 * time.Millisecond}),
+		"Duration 999ms is less than allowed 1s")
+
+	assert.EqualError(t, Validate(struct {
+		A time.Duration `validate:"max:30"`
+		B time.Duration `validate:"min:abc"`
+		C time.Duration `validate:"between:1s"`
+		D time.Duration `validate:"between:1s,x"`
+	}{}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 4))
+}
+
+func TestValidateInBool(t *testing.T) {
+	type terms struct {
+		Accepted bool `validate:"in:true"`
+		Any      bool `validate:"in:true,false"`
+		Opt      bool `validate:"in:0"`
+	}
+	assert.NoError(t, Validate(terms{Accepted: true}))
+	assert.NoError(t, Validate(terms{Accepted: true, Any: true}))
+	assert.EqualError(t, Validate(terms{Accepted: false, Opt: true}), "Field value isn't allowed"+"Field value isn't allowed")
+	assert.EqualError(t, Validate(struct {
+		F bool `validate:"in:true,yes"`
+	}{F: true}), ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateNormalizers(t *testing.T) {
+	type filter struct {
+		Status string   `validate:"in:active,closed"`
+		Tags   []string `validate:"in:go,rust"`
+		Name   string   `validate:"len:3"`
+	}
+	f := filter{Status: "ACTIVE", Tags: []string{"Go", "RUST"}, Name: "  bob "}
+	assert.EqualError(t, Validate(f), "Field value isn't allowed"+"The string on position 0 is not allowed"+"lengths don't match")
+
+	opts := Options{Normalizers: map[string]func(string) string{
+		"Status": strings.ToLower,
+		"Tags":   strings.ToLower,
+		"Name":   strings.TrimSpace,
+	}}
+	assert.NoError(t, ValidateWithOptions(f, opts))
+	// the input is left untouched
+	assert.Equal(t, filter{Status: "ACTIVE", Tags: []string{"Go", "RUST"}, Name: "  bob "}, f)
+}
+
+func TestValidateNumLen(t *testing.T) {
+	type citizen struct {
+		ID    string   `validate:"numlen:11"`
+		Tax   string   `validate:"numlen:9..11"`
+		Other []string `validate:"numlen:2"`
+	}
+	assert.NoError(t, Validate(citizen{ID: "12345678901", Tax: "123456789", Other: []string{"01", "99"}}))
+	assert.NoError(t, Validate(citizen{ID: "00000000000", Tax: "12345678901"}))
+	assert.EqualError(t, Validate(citizen{ID: "1234567890a", Tax: "1234567890"}),
+		"String is not a number of 11 digits")
+	assert.EqualError(t, Validate(citizen{ID: "1234567890", Tax: "123456789012"}),
+		"String is not a number of 11 digits"+"String is not a number of 9 to 11 digits")
+	assert.EqualError(t, Validate(citizen{ID: "12345678901", Tax: "12345-6789", Other: []string{"1"}}),
+		"String is not a number of 9 to 11 digits"+"The string on position 0 is not allowed: String is not a number of 2 digits")
+	assert.EqualError(t, Validate(struct {
+		A string `validate:"numlen:"`
+		B string `validate:"numlen:5..3"`
+		C string `validate:"numlen:3..x"`
+		D int    `validate:"numlen:3"`
+	}{}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 3)+"Field of type int: unsupported field type")
+}
+
+func TestValidateAddr(t *testing.T) {
+	type host struct {
+		Private netip.Addr `validate:"between:10.0.0.0,10.255.255.255"`
+		Public  netip.Addr `validate:"min:11.0.0.0"`
+		Local   netip.Addr `validate:"max:fe80::ffff"`
+	}
+	addr := netip.MustParseAddr
+	assert.NoError(t, Validate(host{Private: addr("10.1.2.3"), Public: addr("11.0.0.0"), Local: addr("::1")}))
+	assert.NoError(t, Validate(host{Private: addr("10.255.255.255"), Public: addr("192.168.0.1"), Local: addr("fe80::ffff")}))
+	assert.EqualError(t, Validate(host{Private: addr("9.255.255.255"), Public: addr("10.0.0.1"), Local: addr("fe80::1:0")}),
+		"Address 9.255.255.255 is less than allowed 10.0.0.0"+
+			"Address 10.0.0.1 is less than allowed 11.0.0.0"+
+			"Address fe80::1:0 is more than allowed fe80::ffff")
+	assert.EqualError(t, Validate(host{Private: addr("11.0.0.0"), Public: addr("11.0.0.0"), Local: addr("::1")}),
+		"Address 11.0.0.0 is more than allowed 10.255.255.255")
+
+	// mixed families can't be compared
+	assert.EqualError(t, Validate(host{Private: addr("::ffff:10.0.0.1"), Public: addr("::1"), Local: addr("127.0.0.1")}),
+		strings.Repeat(ErrInvalidValidatorSyntax.Error(), 3))
+	assert.EqualError(t, Validate(struct {
+		A netip.Addr `validate:"between:10.0.0.0,::1"`
+		B netip.Addr `validate:"min:localhost"`
+		C netip.Addr `validate:"between:10.0.0.0"`
+	}{A: addr("10.0.0.1"), B: addr("10.0.0.1"), C: addr("10.0.0.1")}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 3))
+	assert.EqualError(t, Validate(struct {
+		A netip.Addr `validate:"min:10.0.0.0"`
+	}{}), "Field is not a valid IP address")
+}
+
+func TestValidateJSONNumber(t *testing.T) {
+	type payment struct {
+		Amount json.Number `validate:"min:0"`
+		Rate   json.Number `validate:"between:0,0.5"`
+		Items  json.Number `validate:"max:9007199254740993"`
+	}
+	var p payment
+	assert.NoError(t, json.NewDecoder(strings.NewReader(`{"Amount": 10, "Rate": 0.25, "Items": 9007199254740993}`)).Decode(&p))
+	assert.NoError(t, Validate(p))
+
+	tests := []struct {
+		name    string
+		v       payment
+		wantErr string
+	}{
+		{name: "bounds", v: payment{Amount: "0", Rate: "0.5", Items: "-3"}},
+		{name: "min", v: payment{Amount: "-0.01", Rate: "0", Items: "1"}, wantErr: "Number is less than allowed"},
+		{name: "between", v: payment{Amount: "1", Rate: "0.75", Items: "1"}, wantErr: "Number is more than allowed"},
+		{name: "max beyond float precision", v: payment{Amount: "1", Rate: "0", Items: "9007199254740994"}, wantErr: "Number is more than allowed"},
+		{name: "not a number", v: payment{Amount: "ten", Rate: "0", Items: "1"}, wantErr: `"ten" is not a valid number`},
+		{name: "empty", v: payment{Amount: "1", Rate: "", Items: "1"}, wantErr: `"" is not a valid number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+	assert.EqualError(t, Validate(struct {
+		A json.Number `validate:"min:zero"`
+		B json.Number `validate:"between:1"`
+	}{A: "1", B: "1"}), strings.Repeat(ErrInvalidValidatorSyntax.Error(), 2))
+}
+
+func TestValidateRespectJSONOmitempty(t *testing.T) {
+	type user struct {
+		Nickname string   `json:"nickname,omitempty" validate:"min:3"`
+		Tags     []string `json:"tags,omitempty" validate:"min:4"`
+		Bio      string   `json:"bio" validate:"min:3"`
+		Notes    string   `json:"omitempty" validate:"min:3"`
+	}
+	opts := Options{RespectJSONOmitempty: true}
+	empty := user{Bio: "hey", Notes: "abc"}
+	assert.NoError(t, ValidateWithOptions(empty, opts))
+	assert.EqualError(t, Validate(empty), "String length is less than allowed")
+	assert.EqualError(t, ValidateWithOptions(user{Nickname: "al", Tags: []string{"go"}, Notes: "ab"}, opts),
+		"String length is less than allowed"+"The string on position 0 is shorter than allowed"+
+			"String length is less than allowed"+"String length is less than allowed")
+}
+
+func TestValidateReflectValue(t *testing.T) {
+	// a plugin type only known at run time
+	pluginType := reflect.StructOf([]reflect.StructField{
+		{Name: "Name", Type: reflect.TypeOf(""), Tag: `validate:"min:3"`},
+		{Name: "Started", Type: reflect.TypeOf(time.Time{}), Tag: `validate:"min:2020-01-01"`},
+		{Name: "Workers", Type: reflect.TypeOf(0), Tag: `validate:"between:1,8"`},
+	})
+	plugin := reflect.New(pluginType).Elem()
+	plugin.Field(0).SetString("cron")
+	plugin.Field(1).Set(reflect.ValueOf(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	plugin.Field(2).SetInt(4)
+	assert.NoError(t, Validate(plugin))
+	assert.NoError(t, Validate(plugin.Interface()))
+	assert.NoError(t, ValidateParallel(plugin))
+
+	plugin.Field(0).SetString("ab")
+	plugin.Field(2).SetInt(9)
+	want := "String length is less than allowed" + "Integer is more than allowed"
+	assert.EqualError(t, Validate(plugin), want)
+	assert.EqualError(t, Validate(reflect.ValueOf(plugin.Interface())), want)
+	assert.EqualError(t, ValidateUnsafe(plugin), want)
+
+	// a struct reached through an unexported field is read-only to reflection, whether addressable or not
+	RegisterEnum([]testStatus{testActive, testClosed})
+	t.Cleanup(func() { unregisterEnum(reflect.TypeOf(testActive)) })
+	type wrapper struct {
+		inner struct {
+			Name    string     `validate:"min:3"`
+			Level   status     `validate:"in:Active,Inactive"`
+			Short   version    `validate:"len:3"`
+			Status  testStatus `validate:"enum:"`
+			Started time.Time  `validate:"required:"`
+			Ended   time.Time  `validate:"min:2020-01-01"`
+			Addr    netip.Addr `validate:"min:10.0.0.0"`
+			Big     *big.Int   `validate:"min:1"`
+		}
+	}
+	w := wrapper{}
+	w.inner.Name, w.inner.Level, w.inner.Short, w.inner.Status = "ab", 3, version{10, 0}, 7
+	w.inner.Addr, w.inner.Big = netip.MustParseAddr("10.0.0.1"), big.NewInt(2)
+	want = "String length is less than allowed" + "Field value isn't allowed" +
+		"Field can't be marshaled to text: " + ErrValidateForUnexportedFields.Error() + "unknown is not a valid validation.testStatus" + "Field is required" +
+		strings.Repeat(ErrValidateForUnexportedFields.Error(), 3)
+	assert.EqualError(t, Validate(reflect.ValueOf(&w).Elem().Field(0)), want)
+	assert.EqualError(t, Validate(reflect.ValueOf(w).Field(0)), want)
+	assert.ErrorIs(t, ValidateUnsafe(reflect.ValueOf(w).Field(0)), ErrValidateForUnexportedFields)
+
+	assert.ErrorIs(t, Validate(reflect.Value{}), ErrNotStruct)
+	assert.ErrorIs(t, Validate(nil), ErrNotStruct)
+	assert.ErrorIs(t, Validate(reflect.ValueOf(1)), ErrNotStruct)
+}
+
+func TestValidateTyped(t *testing.T) {
+	type user struct {
+		Name string `validate:"min:3"`
+	}
+	type order struct {
+		Items []int `validate:"max:10"`
+	}
+	assert.NoError(t, ValidateTyped(user{Name: "Bob"}))
+	assert.EqualError(t, ValidateTyped(user{Name: "Al"}), "String length is less than allowed")
+	assert.NoError(t, ValidateTyped(order{Items: []int{1, 10}}))
+	assert.EqualError(t, ValidateTyped(order{Items: []int{11}}), "The integer on position 0 is more than allowed")
+	assert.ErrorIs(t, ValidateTyped(42), ErrNotStruct)
+}
+
+func TestValidateOnlyRunes(t *testing.T) {
+	type ids struct {
+		ID   string   `validate:"onlyrunes:abcdef0123456789-"`
+		Tags []string `validate:"onlyrunes:abcé"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "allowed", v: ids{ID: "0af-19", Tags: []string{"abc", "é", ""}}},
+		{name: "disallowed rune", v: ids{ID: "0AF-19"}, wantErr: `String has characters other than "abcdef0123456789-"`},
+		{name: "disallowed element", v: ids{Tags: []string{"ab", "abd"}}, wantErr: `The string on position 1 is not allowed: String has characters other than "abcé"`},
+		{name: "empty set", v: struct {
+			S string `validate:"onlyrunes:"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateNumericPointers(t *testing.T) {
+	type order struct {
+		Quantity *int     `validate:"min:1;max:10"`
+		Discount *float64 `validate:"between:0,1"`
+		Weight   *float64 `validate:"required:;min:0.5"`
+	}
+	intPtr := func(i int) *int { return &i }
+	floatPtr := func(f float64) *float64 { return &f }
+	tests := []struct {
+		name    string
+		v       order
+		wantErr string
+	}{
+		{name: "nil pointers skip", v: order{Weight: floatPtr(1)}},
+		{name: "populated", v: order{Quantity: intPtr(10), Discount: floatPtr(0.25), Weight: floatPtr(0.5)}},
+		{name: "min", v: order{Quantity: intPtr(0), Weight: floatPtr(1)}, wantErr: "Integer is less than allowed"},
+		{name: "max", v: order{Quantity: intPtr(11), Weight: floatPtr(1)}, wantErr: "Integer is more than allowed"},
+		{name: "between above", v: order{Discount: floatPtr(1.5), Weight: floatPtr(1)}, wantErr: "Number is more than allowed"},
+		{name: "between below", v: order{Discount: floatPtr(-0.1), Weight: floatPtr(1)}, wantErr: "Number is less than allowed"},
+		{name: "float min", v: order{Weight: floatPtr(0.25)}, wantErr: "Number is less than allowed"},
+		{name: "required nil", v: order{}, wantErr: "Field is required"},
+		{name: "required zero", v: order{Weight: floatPtr(0)}, wantErr: "Number is less than allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateNonFiniteFloats(t *testing.T) {
+	type reading struct {
+		Temp    float64   `validate:"min:-50"`
+		Ratio   float32   `validate:"between:0,1"`
+		Samples []float64 `validate:"max:100"`
+		Offset  float64   `validate:"neapprox:1,0.1"`
+		Gain    float64   `validate:"eqapprox:0,1"`
+		Steps   []float64 `validate:"eqapprox:0,1"`
+	}
+	tests := []struct {
+		name    string
+		v       reading
+		wantErr string
+	}{
+		{name: "finite", v: reading{Temp: 20, Ratio: 0.5, Samples: []float64{1, 2}}},
+		{name: "NaN", v: reading{Temp: math.NaN()}, wantErr: "Number is not a finite number"},
+		{name: "+Inf", v: reading{Ratio: float32(math.Inf(1))}, wantErr: "Number is not a finite number"},
+		{name: "-Inf", v: reading{Temp: math.Inf(-1)}, wantErr: "Number is not a finite number"},
+		{name: "NaN element", v: reading{Samples: []float64{1, math.NaN()}}, wantErr: "The number on position 1 is not a finite number"},
+		{name: "NaN neapprox", v: reading{Offset: math.NaN()}, wantErr: "Number is not a finite number"},
+		{name: "+Inf neapprox", v: reading{Offset: math.Inf(1)}, wantErr: "Number is not a finite number"},
+		{name: "NaN eqapprox", v: reading{Gain: math.NaN()}, wantErr: "Number is not a finite number"},
+		{name: "-Inf eqapprox", v: reading{Gain: math.Inf(-1)}, wantErr: "Number is not a finite number"},
+		{name: "Inf eqapprox element", v: reading{Steps: []float64{0.5, math.Inf(1)}}, wantErr: "The number on position 1 is not a finite number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	assert.EqualError(t, Validate(struct {
+		F float64 `validate:"max:NaN"`
+		G float64 `validate:"eqapprox:NaN,1"`
+	}{}), ErrInvalidValidatorSyntax.Error()+ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateRequiredTime(t *testing.T) {
+	type audit struct {
+		CreatedAt time.Time  `validate:"required:"`
+		DeletedAt *time.Time `validate:"isdefault:"`
+	}
+	local := time.FixedZone("UTC+3", 3*60*60)
+	zeroInLocal := time.Time{}.In(local)
+	assert.False(t, reflect.ValueOf(zeroInLocal).IsZero())
+
+	assert.NoError(t, Validate(audit{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, local)}))
+	assert.EqualError(t, Validate(audit{}), "Field is required")
+	assert.EqualError(t, Validate(audit{CreatedAt: zeroInLocal}), "Field is required")
+	now := time.Now()
+	assert.EqualError(t, Validate(audit{CreatedAt: now, DeletedAt: &now}), "Field must not be set")
+}
+
+func TestValidateRequiredSlice(t *testing.T) {
+	type patch struct {
+		Tags   []string          `validate:"required:"`
+		Roles  []string          `validate:"required:nonnil"`
+		Emails []string          `validate:"required:nonempty"`
+		Labels map[string]string `validate:"required:nonnil"`
+	}
+	full := []string{"a"}
+	labels := map[string]string{}
+	tests := []struct {
+		name string
+		in   patch
+		err  string
+	}{
+		{name: "populated", in: patch{Tags: full, Roles: full, Emails: full, Labels: labels}},
+		{name: "nil", in: patch{}, err: "Field is required" + "Field is required" + "Field must not be empty" + "Field is required"},
+		{
+			name: "empty",
+			in:   patch{Tags: []string{}, Roles: []string{}, Emails: []string{}, Labels: labels},
+			err:  "Field is required" + "Field must not be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.in)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	assert.EqualError(t, Validate(struct {
+		Count int `validate:"required:nonnil"`
+	}{Count: 1}), "Field of type int: unsupported field type")
+	assert.EqualError(t, Validate(struct {
+		Tags []string `validate:"required:nonzero"`
+	}{Tags: full}), ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateNonNil(t *testing.T) {
+	type server struct {
+		Handler func()            `validate:"nonnil:"`
+		Done    chan struct{}     `validate:"nonnil:"`
+		Routes  map[string]string `validate:"nonnil:"`
+		Logger  *strings.Builder  `validate:"nonnil:"`
+		Err     error             `validate:"nonnil:"`
+		Args    []string          `validate:"nonnil:"`
+	}
+	valid := server{
+		Handler: func() {},
+		Done:    make(chan struct{}),
+		Routes:  map[string]string{},
+		Logger:  &strings.Builder{},
+		Err:     errors.New("closed"),
+		Args:    []string{},
+	}
+	assert.NoError(t, Validate(valid))
+
+	for i, name := range []string{"Handler", "Done", "Routes", "Logger", "Err", "Args"} {
+		t.Run(name, func(t *testing.T) {
+			in := valid
+			field := reflect.ValueOf(&in).Elem().Field(i)
+			field.Set(reflect.Zero(field.Type()))
+			err := Validate(in)
+			e := ValidationErrors{}
+			assert.True(t, errors.As(err, &e))
+			assert.Len(t, e, 1)
+			assert.Equal(t, name, e[0].Field)
+			assert.EqualError(t, err, "Field must not be nil")
+		})
+	}
+
+	assert.EqualError(t, Validate(struct {
+		Count int `validate:"nonnil:"`
+	}{}), "Field of type int: unsupported field type")
+}
+
+func TestValidateIsDefault(t *testing.T) {
+	type createRequest struct {
+		ID        int               `validate:"isdefault:"`
+		CreatedBy string            `validate:"isdefault:"`
+		Owner     *string           `validate:"isdefault:"`
+		Labels    map[string]string `validate:"isdefault:"`
+		Name      string            `validate:"min:1"`
+	}
+	owner, empty := "bob", ""
+	assert.NoError(t, Validate(createRequest{Name: "doc"}))
+	assert.EqualError(t, Validate(createRequest{ID: 7, CreatedBy: "bob", Owner: &owner, Labels: map[string]string{}, Name: "doc"}),
+		strings.Repeat("Field must not be set", 4))
+	// a set pointer is not the default, even if it points to a zero value
+	assert.EqualError(t, Validate(createRequest{Owner: &empty, Name: "doc"}), "Field must not be set")
+	assert.EqualError(t, Validate(struct {
+		ID int `validate:"isdefault:0"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateEmailDomain(t *testing.T) {
+	type invite struct {
+		Email string   `validate:"emaildomain:example.com,Corp.com"`
+		CC    []string `validate:"emaildomain:example.com"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "allowed", v: invite{Email: "bob@corp.com", CC: []string{"a@EXAMPLE.com"}}},
+		{name: "case-insensitive", v: invite{Email: "Bob@Example.COM"}},
+		{name: "disallowed domain", v: invite{Email: "bob@gmail.com"}, wantErr: `Email domain "gmail.com" is not allowed`},
+		{name: "subdomain", v: invite{Email: "bob@mail.example.com"}, wantErr: `Email domain "mail.example.com" is not allowed`},
+		{name: "not an email", v: invite{Email: "bob"}, wantErr: `"bob" is not a valid email address`},
+		{name: "display name", v: invite{Email: "Bob <bob@example.com>"}, wantErr: `"Bob <bob@example.com>" is not a valid email address`},
+		{name: "element", v: invite{Email: "a@example.com", CC: []string{"b@example.com", "c@corp.com"}},
+			wantErr: `The string on position 1 is not allowed: Email domain "corp.com" is not allowed`},
+		{name: "no domains", v: struct {
+			E string `validate:"emaildomain:"`
+		}{E: "a@example.com"}, wantErr: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateBitmask(t *testing.T) {
+	type file struct {
+		Flags uint  `validate:"bitmask:0b111"`
+		Mode  int   `validate:"bitmask:0o755"`
+		Perms uint8 `validate:"bitmask:require,0x6"`
+	}
+	tests := []struct {
+		name    string
+		v       file
+		wantErr string
+	}{
+		{name: "within masks", v: file{Flags: 5, Mode: 0o644, Perms: 2}},
+		{name: "zero", v: file{Perms: 4}},
+		{name: "out of mask", v: file{Flags: 0b1010, Perms: 2}, wantErr: "Bits 0b1000 are not allowed by mask 0b111"},
+		{name: "octal", v: file{Mode: 0o1777, Perms: 2}, wantErr: "Bits 0b1000010010 are not allowed by mask 0b111101101"},
+		{name: "required missing", v: file{Perms: 0}, wantErr: "None of the bits of mask 0b110 is set"},
+		{name: "required out of mask", v: file{Perms: 3}, wantErr: "Bits 0b1 are not allowed by mask 0b110"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+	assert.EqualError(t, Validate(struct {
+		A int    `validate:"bitmask:seven"`
+		B string `validate:"bitmask:7"`
+		C int    `validate:"bitmask:require"`
+	}{}), ErrInvalidValidatorSyntax.Error()+"Field of type string: unsupported field type"+ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateIRegexp(t *testing.T) {
+	type names struct {
+		Strict  string   `validate:"regexp:^abc$"`
+		Inline  string   `validate:"regexp:(?i)^abc$"`
+		Relaxed string   `validate:"iregexp:^abc$"`
+		Tags    []string `validate:"iregexp:^go-"`
+	}
+	assert.NoError(t, Validate(names{Strict: "abc", Inline: "abc", Relaxed: "abc", Tags: []string{"go-lang"}}))
+	assert.NoError(t, Validate(names{Strict: "abc", Inline: "ABC", Relaxed: "AbC", Tags: []string{"GO-lang", "Go-vet"}}))
+	assert.EqualError(t, Validate(names{Strict: "ABC", Inline: "abd", Relaxed: "abcd", Tags: []string{"rust"}}),
+		`String doesn't match "^abc$"`+`String doesn't match "(?i)^abc$"`+
+			`String doesn't match "^abc$" case-insensitively`+
+			`The string on position 0 is not allowed: String doesn't match "^go-" case-insensitively`)
+	assert.EqualError(t, Validate(struct {
+		F string `validate:"iregexp:[a-"`
+	}{}), ErrInvalidValidatorSyntax.Error())
+}
+
+func TestValidateRegexpQuoted(t *testing.T) {
+	type choice struct {
+		Letter string `validate:"regexp:'^(a|b)$'"`
+		Pair   string `validate:"iregexp:'^x;y$'|len:1"`
+		Quote  string `validate:"regexp:'^\\'$'"`
+	}
+	tests := []struct {
+		name    string
+		v       choice
+		wantErr string
+	}{
+		{name: "a", v: choice{Letter: "a", Pair: "X;Y", Quote: "'"}},
+		{name: "b", v: choice{Letter: "b", Pair: "z", Quote: "'"}},
+		{name: "c", v: choice{Letter: "c", Pair: "x;y", Quote: "'"}, wantErr: `String doesn't match "^(a|b)$"`},
+		{name: "alternative", v: choice{Letter: "a", Pair: "xy", Quote: "'"},
+			wantErr: `String doesn't match "^x;y$" case-insensitively or lengths don't match`},
+		{name: "escaped quote", v: choice{Letter: "a", Pair: "z", Quote: "x"}, wantErr: `String doesn't match "^'$"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidatePrefixSuffixAny(t *testing.T) {
+	type upload struct {
+		Image  string   `validate:"suffixany:.jpg,.png,.gif"`
+		Source string   `validate:"prefixany:http://,https://"`
+		Files  []string `validate:"suffixany:.go"`
+	}
+	assert.NoError(t, Validate(upload{Image: "cat.png", Source: "https://example.com", Files: []string{"a.go", "b_test.go"}}))
+	assert.EqualError(t, Validate(upload{Image: "cat.png.exe", Source: "ftp://example.com", Files: []string{"a.go", "go.mod"}}),
+		`String doesn't end with any of ".jpg,.png,.gif"`+`String doesn't start with any of "http://,https://"`+
+			`The string on position 1 is not allowed: String doesn't end with any of ".go"`)
+	assert.EqualError(t, Validate(struct {
+		A string `validate:"suffixany:"`
+		B int    `validate:"prefixany:1"`
+	}{}), ErrInvalidValidatorSyntax.Error()+"Field of type int: unsupported field type")
+}
+
+func TestValidateHexColor(t *testing.T) {
+	type theme struct {
+		Color   string   `validate:"hexcolor:"`
+		Palette []string `validate:"hexcolor:"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "valid", v: theme{Color: "#FFF", Palette: []string{"#AABBCC", "abc", "0f0f0f"}}},
+		{name: "bad length", v: theme{Color: "#ABCD"}, wantErr: `"#ABCD" is not a valid hex color`},
+		{name: "bad digit", v: theme{Color: "#GGG"}, wantErr: `"#GGG" is not a valid hex color`},
+		{name: "bad element", v: theme{Color: "fff", Palette: []string{"#000", "##000"}}, wantErr: `The string on position 1 is not a valid hex color: "##000"`},
+		{name: "argument", v: struct {
+			C string `validate:"hexcolor:rgb"`
+		}{C: "#fff"}, wantErr: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateAtLeastOne(t *testing.T) {
+	type contact struct {
+		Email string
+		Phone *string
+		Age   int `validate:"min:18"`
+	}
+	phone := ""
+	assert.NoError(t, ValidateAtLeastOne(contact{Email: "bob@example.com"}, "Email", "Phone"))
+	assert.NoError(t, ValidateAtLeastOne(contact{Phone: &phone}, "Email", "Phone"))
+
+	err := ValidateAtLeastOne(contact{Age: 30}, "Email", "Phone")
+	assert.EqualError(t, err, "At least one of the fields Email, Phone must be set")
+	assert.IsType(t, ValidationErrors{}, err)
+
+	assert.EqualError(t, ValidateAtLeastOne(contact{}, "Email", "Fax"), "Struct has no field Fax")
+	assert.EqualError(t, ValidateAtLeastOne(contact{}), "No fields given to check")
+	assert.ErrorIs(t, ValidateAtLeastOne("contact", "Email"), ErrNotStruct)
+}
+
+func TestValidateSQLNull(t *testing.T) {
+	type customer struct {
+		Name     sql.NullString  `validate:"min:3"`
+		Age      sql.NullInt64   `validate:"between:18,120"`
+		Discount sql.NullFloat64 `validate:"max:0.5"`
+		Joined   sql.NullTime    `validate:"min:2020-01-01"`
+		Email    sql.NullString  `validate:"required:;regexp:@"`
+	}
+	email := sql.NullString{String: "bob@example.com", Valid: true}
+	assert.NoError(t, Validate(customer{Email: email}))
+	assert.NoError(t, Validate(customer{
+		Name:     sql.NullString{String: "Bob", Valid: true},
+		Age:      sql.NullInt64{Int64: 30, Valid: true},
+		Discount: sql.NullFloat64{Float64: 0.5, Valid: true},
+		Joined:   sql.NullTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Email:    email,
+	}))
+	// an invalid value is skipped even if the underlying one would fail
+	assert.NoError(t, Validate(customer{Name: sql.NullString{String: "x"}, Email: email}))
+
+	assert.EqualError(t, Validate(customer{
+		Name:     sql.NullString{String: "Al", Valid: true},
+		Age:      sql.NullInt64{Int64: 0, Valid: true},
+		Discount: sql.NullFloat64{Float64: 0.75, Valid: true},
+		Joined:   sql.NullTime{Valid: true},
+		Email:    sql.NullString{String: "bob", Valid: true},
+	}), "String length is less than allowed"+"Integer is more than allowed"+"Number is more than allowed"+
+		"Time 0001-01-01T00:00:00Z is before allowed 2020-01-01T00:00:00Z"+`String doesn't match "@"`)
+	assert.EqualError(t, Validate(customer{}), "Field is required")
+}
+
+func TestValidateFieldOrder(t *testing.T) {
+	type header struct {
+		Magic   uint32
+		Version uint8
+		flags   uint8
+		Length  uint16
+	}
+	assert.NoError(t, ValidateFieldOrder(header{flags: 1}, []string{"Magic", "Version", "Length"}))
+	assert.EqualError(t, ValidateFieldOrder(header{}, []string{"Magic", "Length", "Version"}),
+		"Field at position 1 is Version, expected Length")
+	assert.EqualError(t, ValidateFieldOrder(header{}, []string{"Magic", "Version"}), "Unexpected field Length at position 2")
+	assert.EqualError(t, ValidateFieldOrder(header{}, []string{"Magic", "Version", "Length", "CRC"}), "Missing field CRC at position 3")
+	assert.ErrorIs(t, ValidateFieldOrder([]string{"Magic"}, nil), ErrNotStruct)
+}
+
+func TestValidateExactlyOne(t *testing.T) {
+	type payment struct {
+		Card   *string
+		IBAN   string
+		Wallet string
+	}
+	card := "4242"
+	assert.NoError(t, ValidateExactlyOne(payment{Card: &card}, "Card", "IBAN", "Wallet"))
+	assert.NoError(t, ValidateExactlyOne(payment{Wallet: "w"}, "Card", "IBAN", "Wallet"))
+	assert.EqualError(t, ValidateExactlyOne(payment{}, "Card", "IBAN", "Wallet"),
+		"Exactly one of the fields Card, IBAN, Wallet must be set, none is")
+	assert.EqualError(t, ValidateExactlyOne(payment{Card: &card, IBAN: "DE00", Wallet: "w"}, "Card", "IBAN", "Wallet"),
+		"Exactly one of the fields Card, IBAN, Wallet must be set, 3 are")
+	// only the named fields count
+	assert.NoError(t, ValidateExactlyOne(payment{IBAN: "DE00", Wallet: "w"}, "Card", "IBAN"))
+	assert.EqualError(t, ValidateExactlyOne(payment{}, "Card", "Cash"), "Struct has no field Cash")
+	assert.ErrorIs(t, ValidateExactlyOne(nil, "Card"), ErrNotStruct)
+}
+
+func TestValidateUniqueFold(t *testing.T) {
+	type request struct {
+		Headers map[string]string `validate:"uniquefold:"`
+	}
+	assert.NoError(t, Validate(request{}))
+	assert.NoError(t, Validate(request{Headers: map[string]string{"Content-Type": "text/plain", "Accept": "*/*"}}))
+	assert.EqualError(t, Validate(request{Headers: map[string]string{
+		"Content-Type": "text/plain",
+		"content-type": "application/json",
+		"Accept":       "*/*",
+	}}), `Keys "Content-Type" and "content-type" collide case-insensitively`)
+	assert.EqualError(t, Validate(struct {
+		Tags []string `validate:"uniquefold:"`
+	}{Tags: []string{"a"}}), "Field of type []string: unsupported field type")
+}
+
+type testNode struct {
+	Name string `validate:"min:1"`
+	Next *testNode
+}
+
+func TestValidateNested(t *testing.T) {
+	type address struct {
+		City string `validate:"min:1"`
+	}
+	type order struct {
+		ID       int `validate:"min:1"`
+		Shipping address
+		Billing  *address
+		Created  time.Time `validate:"required:"`
+	}
+	assert.NoError(t, Validate(order{ID: 1, Shipping: address{City: "Oslo"}, Created: time.Now()}))
+
+	err := Validate(order{ID: 1, Billing: &address{}, Created: time.Now()})
+	e := ValidationErrors{}
+	assert.True(t, errors.As(err, &e))
+	assert.Len(t, e, 2)
+	assert.Equal(t, "Shipping.City", e[0].Field)
+	assert.Equal(t, "Billing.City", e[1].Field)
+	assert.Equal(t, "String length is less than allowed"+"String length is less than allowed", err.Error())
+	assert.Equal(t, "2 field(s) failed: Shipping.City, Billing.City", Summary(order{ID: 1, Billing: &address{}, Created: time.Now()}))
+
+	// the outer fields only for ValidateSpec
+	assert.NoError(t, ValidateSpec(order{}, map[string]string{"ID": "max:1"}))
+}
+
+func TestValidateNestedCycle(t *testing.T) {
+	a := &testNode{Name: "a"}
+	b := &testNode{Next: a}
+	a.Next = b
+
+	err := Validate(*a)
+	e := ValidationErrors{}
+	assert.True(t, errors.As(err, &e))
+	assert.Len(t, e, 1)
+	assert.Equal(t, "Next.Name", e[0].Field)
+
+	assert.Error(t, ValidateParallel(*a))
+
+	// a cycle back to the validated struct doesn't validate it again, whether it is a copy or not
+	self := &testNode{}
+	self.Next = self
+	assert.EqualError(t, Validate(*self), "String length is less than allowed")
+	assert.EqualError(t, Validate(reflect.ValueOf(self).Elem()), "String length is less than allowed")
+	assert.EqualError(t, ValidateParallel(*self), "String length is less than allowed")
+	a.Name = ""
+	err = Validate(*a)
+	assert.True(t, errors.As(err, &e))
+	assert.Len(t, e, 2)
+	assert.Equal(t, "Name", e[0].Field)
+	assert.Equal(t, "Next.Name", e[1].Field)
+}
+
+func TestValidateMaxDepth(t *testing.T) {
+	chain := &testNode{Name: "1"}
+	for _, name := range []string{"2", "3", "4"} {
+		chain = &testNode{Name: name, Next: chain}
+	}
+	assert.NoError(t, Validate(*chain))
+	assert.NoError(t, ValidateWithOptions(*chain, Options{MaxDepth: 3}))
+
+	err := ValidateWithOptions(*chain, Options{MaxDepth: 2})
+	assert.ErrorIs(t, err, ErrMaxDepth)
+	assert.EqualError(t, err, "Next.Next.Next: "+ErrMaxDepth.Error())
+
+	// deep data without rules is not validated, so it doesn't hit the limit
+	type link struct {
+		Value int
+		Next  *link
+	}
+	type list struct {
+		Name string `validate:"min:1"`
+		Head *link
+	}
+	var head *link
+	for i := 0; i < 40; i++ {
+		head = &link{Value: i, Next: head}
+	}
+	assert.NoError(t, Validate(list{Name: "l", Head: head}))
+	assert.NoError(t, ValidateWithOptions(list{Name: "l", Head: head}, Options{MaxDepth: 1}))
+	for i := 0; i < 40; i++ {
+		chain = &testNode{Name: "n", Next: chain}
+	}
+	assert.ErrorIs(t, Validate(*chain), ErrMaxDepth)
+}
+
+func TestValidateReversedRange(t *testing.T) {
+	type reversed struct {
+		Count   int           `validate:"between:100,0"`
+		Ratio   float64       `validate:"between:1,0.5"`
+		Name    string        `validate:"between:8,2"`
+		Timeout time.Duration `validate:"between:1m,1s"`
+		Start   time.Time     `validate:"between:2030-01-01,2020-01-01"`
+		Amount  json.Number   `validate:"between:10,1"`
+		Host    netip.Addr    `validate:"between:10.255.255.255,10.0.0.0"`
+		Fee     *big.Int      `validate:"between:100,-1"`
+	}
+	valid := reversed{
+		Count:   50,
+		Ratio:   0.75,
+		Name:    "abcd",
+		Timeout: time.Second * 30,
+		Start:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Amount:  "5",
+		Host:    netip.MustParseAddr("10.1.2.3"),
+		Fee:     big.NewInt(7),
+	}
+	err := Validate(valid)
+	e := ValidationErrors{}
+	assert.True(t, errors.As(err, &e))
+	assert.Len(t, e, 8)
+	for _, ve := range e {
+		assert.ErrorIs(t, ve.Err, ErrInvalidValidatorSyntax, ve.Field)
+	}
+
+	opts := Options{AllowReversedRange: true}
+	assert.NoError(t, ValidateWithOptions(valid, opts))
+	out := valid
+	out.Count, out.Ratio, out.Timeout = 101, 0.25, time.Hour
+	assert.EqualError(t, ValidateWithOptions(out, opts), "Integer is more than allowed"+
+		"Number is less than allowed"+
+		"Duration 1h0m0s is more than allowed 1m0s")
+
+	// equal bounds are not reversed
+	assert.NoError(t, Validate(struct {
+		N int `validate:"between:3,3"`
+	}{N: 3}))
+}
+
+func TestValidateWithDefaults(t *testing.T) {
+	type profile struct {
+		Name     string
+		Nickname *string
+		Bio      string `validate:"len:0"`
+		Age      int
+		Score    int `validate:"max:10"`
+		secret   string
+	}
+	defaults := map[reflect.Kind]string{reflect.String: "notblank:", reflect.Int: "min:1"}
+	nick := "jd"
+	assert.NoError(t, ValidateWithDefaults(profile{Name: "Jane", Nickname: &nick, Age: 30}, defaults))
+
+	blank := " "
+	err := ValidateWithDefaults(profile{Nickname: &blank, Bio: "x", Score: 11, secret: ""}, defaults)
+	e := ValidationErrors{}
+	assert.True(t, errors.As(err, &e))
+	fields := make([]string, 0, len(e))
+	for _, ve := range e {
+		fields = append(fields, ve.Field)
+	}
+	assert.Equal(t, []string{"Name", "Nickname", "Bio", "Age", "Score"}, fields)
+
+	// no defaults for the kind
+	assert.NoError(t, ValidateWithDefaults(profile{Bio: ""}, map[reflect.Kind]string{reflect.Bool: "required:"}))
+	assert.NoError(t, Validate(profile{}))
+}
+
+func TestValidateLenMap(t *testing.T) {
+	type config struct {
+		Overrides map[string]string `validate:"len:0"`
+		Pair      map[string]int    `validate:"len:2"`
+	}
+	assert.NoError(t, Validate(config{Pair: map[string]int{"a": 1, "b": 2}}))
+	assert.NoError(t, Validate(config{Overrides: map[string]string{}, Pair: map[string]int{"a": 1, "b": 2}}))
+	assert.EqualError(t, Validate(config{Overrides: map[string]string{"debug": "true"}, Pair: map[string]int{"a": 1}}),
+		"Map has 1 entries, expected 0"+"Map has 1 entries, expected 2")
+}
+
+func TestValidateBetweenUint(t *testing.T) {
+	type person struct {
+		Age    uint8    `validate:"between:0,120"`
+		Shares uint64   `validate:"between:1,18446744073709551615"`
+		Ports  []uint16 `validate:"between:1024,65535"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "lower bounds", v: person{Age: 0, Shares: 1, Ports: []uint16{1024}}},
+		{name: "upper bounds", v: person{Age: 120, Shares: math.MaxUint64, Ports: []uint16{65535}}},
+		{name: "above", v: person{Age: 121, Shares: 1}, wantErr: "Integer is more than allowed"},
+		{name: "below", v: person{Age: 1}, wantErr: "Integer is less than allowed"},
+		{name: "slice below", v: person{Shares: 1, Ports: []uint16{8080, 1023}}, wantErr: "The integer on position 1 is less than allowed"},
+		{name: "negative bound", v: struct {
+			U uint `validate:"between:-1,5"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+		{name: "reversed", v: struct {
+			U uint `validate:"between:5,1"`
+		}{U: 3}, wantErr: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateOptionalTimes(t *testing.T) {
+	type record struct {
+		DeletedAt  *time.Time   `validate:"max:2100-01-01"`
+		ArchivedAt sql.NullTime `validate:"between:2000-01-01,2100-01-01"`
+		ReviewedAt *time.Time   `validate:"required:;min:2020-01-01"`
+	}
+	inRange := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tooLate := time.Date(2101, 1, 1, 0, 0, 0, 0, time.UTC)
+	tooEarly := time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		v       record
+		wantErr string
+	}{
+		{name: "nil and invalid skip", v: record{ReviewedAt: &inRange, ArchivedAt: sql.NullTime{Time: tooEarly}}},
+		{name: "set", v: record{DeletedAt: &inRange, ArchivedAt: sql.NullTime{Time: inRange, Valid: true}, ReviewedAt: &inRange}},
+		{name: "pointer out of range", v: record{DeletedAt: &tooLate, ReviewedAt: &inRange},
+			wantErr: "Time 2101-01-01T00:00:00Z is after allowed 2100-01-01T00:00:00Z"},
+		{name: "NullTime out of range", v: record{ArchivedAt: sql.NullTime{Time: tooEarly, Valid: true}, ReviewedAt: &inRange},
+			wantErr: "Time 1999-01-01T00:00:00Z is before allowed 2000-01-01T00:00:00Z"},
+		{name: "required nil", v: record{}, wantErr: "Field is required"},
+		{name: "required out of range", v: record{ReviewedAt: &tooEarly},
+			wantErr: "Time 1999-01-01T00:00:00Z is before allowed 2020-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateField(t *testing.T) {
+	name := "bob"
+	tests := []struct {
+		name    string
+		value   any
+		rules   string
+		wantErr string
+	}{
+		{name: "len", value: "abc", rules: "len:3"},
+		{name: "len mismatch", value: "abcd", rules: "len:3", wantErr: "lengths don't match"},
+		{name: "in", value: "b", rules: "in:a,b"},
+		{name: "in mismatch", value: 3, rules: "in:1,2", wantErr: "Field value isn't allowed"},
+		{name: "min", value: int64(18), rules: "min:18"},
+		{name: "min mismatch", value: []int{20, 5}, rules: "min:10", wantErr: "The integer on position 1 is less than allowed"},
+		{name: "pointer", value: &name, rules: "min:3;max:32"},
+		{name: "dive", value: []string{"ok", "x"}, rules: "dive;len:2", wantErr: "[1]: lengths don't match"},
+		{name: "warn only", value: "x", rules: "warn;min:3"},
+		{name: "nil", value: nil, rules: "required:", wantErr: "Field is required"},
+		{name: "nil skips", value: (*string)(nil), rules: "min:3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateField(tt.value, tt.rules)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			e := ValidationErrors{}
+			assert.True(t, errors.As(err, &e))
+		})
+	}
+
+	for _, rules := range []string{"len", "len:x", "min:1;;max:2", "dive", "nosuch:1", "maxlenfield:Other", "skipif:Mode x;min:1"} {
+		t.Run(rules, func(t *testing.T) {
+			err := ValidateField("abc", rules)
+			if rules == "nosuch:1" {
+				assert.EqualError(t, err, "Unexpected validator option")
+				return
+			}
+			if rules == "dive" {
+				assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidValidatorSyntax)
+		})
+	}
+}
+
+func TestValidateUnsupportedFieldType(t *testing.T) {
+	tests := []struct {
+		name            string
+		v               any
+		wantUnsupported bool
+		wantErr         string
+	}{
+		{name: "len on int", v: struct {
+			F int `validate:"len:3"`
+		}{}, wantUnsupported: true, wantErr: "Field of type int: unsupported field type"},
+		{name: "regexp on bool", v: struct {
+			F bool `validate:"regexp:^a$"`
+		}{}, wantUnsupported: true, wantErr: "Field of type bool: unsupported field type"},
+		{name: "min on struct slice", v: struct {
+			F []struct{} `validate:"min:1"`
+		}{F: []struct{}{{}}}, wantUnsupported: true, wantErr: "Field of type []struct {}: unsupported field type"},
+		{name: "nonnil on string", v: struct {
+			F string `validate:"nonnil:"`
+		}{}, wantUnsupported: true, wantErr: "Field of type string: unsupported field type"},
+		{name: "lenmin on int", v: struct {
+			F int `validate:"lenmin:1"`
+		}{}, wantUnsupported: true, wantErr: "Field of type int: unsupported field type"},
+		{name: "lenmax on float", v: struct {
+			F float64 `validate:"lenmax:1"`
+		}{}, wantUnsupported: true, wantErr: "Field of type float64: unsupported field type"},
+		{name: "digits on string", v: struct {
+			F string `validate:"digits:3"`
+		}{}, wantUnsupported: true, wantErr: "Field of type string: unsupported field type"},
+		{name: "digitsbetween on strings", v: struct {
+			F []string `validate:"digitsbetween:1,3"`
+		}{}, wantUnsupported: true, wantErr: "Field of type []string: unsupported field type"},
+		{name: "maxlenfield on int", v: struct {
+			F int `validate:"maxlenfield:G"`
+			G string
+		}{}, wantUnsupported: true, wantErr: "Field of type int: unsupported field type"},
+		{name: "dive on int", v: struct {
+			F int `validate:"dive;min:1"`
+		}{}, wantUnsupported: true, wantErr: "Field of type int: unsupported field type"},
+		{name: "malformed lenmin", v: struct {
+			F string `validate:"lenmin:x"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+		{name: "malformed digits", v: struct {
+			F int `validate:"digits:0"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+		{name: "malformed len", v: struct {
+			F string `validate:"len:x"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+		{name: "malformed nonnil", v: struct {
+			F *int `validate:"nonnil:yes"`
+		}{}, wantErr: ErrInvalidValidatorSyntax.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			assert.EqualError(t, err, tt.wantErr)
+			e := ValidationErrors{}
+			assert.True(t, errors.As(err, &e))
+			assert.Len(t, e, 1)
+			assert.Equal(t, tt.wantUnsupported, errors.Is(e[0].Err, ErrUnsupportedFieldType))
+			assert.Equal(t, !tt.wantUnsupported, errors.Is(e[0].Err, ErrInvalidValidatorSyntax))
+		})
+	}
+
+	err := ValidateField(3, "len:3")
+	assert.ErrorIs(t, err, ErrUnsupportedFieldType)
+	assert.NotErrorIs(t, err, ErrInvalidValidatorSyntax)
+	assert.EqualError(t, err, "Field of type int: unsupported field type")
+}
+
+func TestValidateRuneRange(t *testing.T) {
+	type document struct {
+		Title    string   `validate:"runerange:0,127"`
+		Keywords []string `validate:"runerange:0x400,0x4FF"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "in range", v: document{Title: "Hello, world!", Keywords: []string{"привет", "мир"}}},
+		{name: "upper bound", v: document{Title: "\x7f"}},
+		{name: "accented", v: document{Title: "café"}, wantErr: `String has characters outside of the range 0,127`},
+		{name: "emoji", v: document{Title: "hi 👋"}, wantErr: `String has characters outside of the range 0,127`},
+		{name: "slice", v: document{Keywords: []string{"мир", "mир"}},
+			wantErr: "The string on position 1 is not allowed: String has characters outside of the range 0x400,0x4FF"},
+		{name: "malformed", v: struct {
+			A string `validate:"runerange:127"`
+			B string `validate:"runerange:a,z"`
+			C string `validate:"runerange:127,0"`
+			D int    `validate:"runerange:0,127"`
+		}{}, wantErr: strings.Repeat(ErrInvalidValidatorSyntax.Error(), 3) + "Field of type int: unsupported field type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateCount(t *testing.T) {
+	type upload struct {
+		Header string   `validate:"count:,,3"`
+		Rows   []string `validate:"count:,,3"`
+		Path   string   `validate:"count:../,0"`
+	}
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "exact", v: upload{Header: "id,name,email,age", Rows: []string{"1,a,b,2", ",,,"}, Path: "data/in.csv"}},
+		{name: "too few", v: upload{Header: "id,name"}, wantErr: `String contains "," 1 times, expected 3`},
+		{name: "too many", v: upload{Header: "a,b,c,d", Path: "../../etc"}, wantErr: `String contains "../" 2 times, expected 0`},
+		{name: "row", v: upload{Header: "a,b,c,d", Rows: []string{"1,2,3,4", "1,2,3,4,5"}},
+			wantErr: `The string on position 1 contains "," 4 times, expected 3`},
+		{name: "malformed", v: struct {
+			A string `validate:"count:3"`
+			B string `validate:"count:,"`
+			C string `validate:"count:a,-1"`
+			D string `validate:"count:,x"`
+			E int    `validate:"count:a,1"`
+		}{}, wantErr: strings.Repeat(ErrInvalidValidatorSyntax.Error(), 4) + "Field of type int: unsupported field type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}